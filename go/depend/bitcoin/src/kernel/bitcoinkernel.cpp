@@ -9,7 +9,10 @@
 #include <chain.h>
 #include <coins.h>
 #include <consensus/amount.h>
+#include <consensus/params.h>
 #include <consensus/validation.h>
+#include <deploymentstatus.h>
+#include <hash.h>
 #include <kernel/caches.h>
 #include <kernel/chainparams.h>
 #include <kernel/checks.h>
@@ -20,6 +23,7 @@
 #include <logging.h>
 #include <node/blockstorage.h>
 #include <node/chainstate.h>
+#include <pow.h>
 #include <primitives/block.h>
 #include <primitives/transaction.h>
 #include <script/interpreter.h>
@@ -37,7 +41,9 @@
 #include <util/translation.h>
 #include <validation.h>
 #include <validationinterface.h>
+#include <versionbits.h>
 
+#include <algorithm>
 #include <cassert>
 #include <cstddef>
 #include <cstring>
@@ -45,8 +51,10 @@
 #include <functional>
 #include <list>
 #include <memory>
+#include <optional>
 #include <span>
 #include <string>
+#include <string_view>
 #include <tuple>
 #include <utility>
 #include <vector>
@@ -324,6 +332,59 @@ public:
     }
 };
 
+//! Forwards the notifications of a context's chainstate manager to the user's
+//! notifications, and interrupts the context on errors if requested through
+//! btck_context_options_set_interrupt_on_error.
+class ContextNotifications final : public kernel::Notifications
+{
+private:
+    std::shared_ptr<KernelNotifications> m_notifications;
+    util::SignalInterrupt& m_interrupt;
+    const bool m_interrupt_on_fatal_error;
+    const bool m_interrupt_on_flush_error;
+
+public:
+    ContextNotifications(std::shared_ptr<KernelNotifications> notifications, util::SignalInterrupt& interrupt,
+                         bool interrupt_on_fatal_error, bool interrupt_on_flush_error)
+        : m_notifications{std::move(notifications)},
+          m_interrupt{interrupt},
+          m_interrupt_on_fatal_error{interrupt_on_fatal_error},
+          m_interrupt_on_flush_error{interrupt_on_flush_error}
+    {
+    }
+
+    kernel::InterruptResult blockTip(SynchronizationState state, const CBlockIndex& index, double verification_progress) override
+    {
+        return m_notifications->blockTip(state, index, verification_progress);
+    }
+    void headerTip(SynchronizationState state, int64_t height, int64_t timestamp, bool presync) override
+    {
+        m_notifications->headerTip(state, height, timestamp, presync);
+    }
+    void progress(const bilingual_str& title, int progress_percent, bool resume_possible) override
+    {
+        m_notifications->progress(title, progress_percent, resume_possible);
+    }
+    void warningSet(kernel::Warning id, const bilingual_str& message) override
+    {
+        m_notifications->warningSet(id, message);
+    }
+    void warningUnset(kernel::Warning id) override
+    {
+        m_notifications->warningUnset(id);
+    }
+    void flushError(const bilingual_str& message) override
+    {
+        m_notifications->flushError(message);
+        if (m_interrupt_on_flush_error) (void)m_interrupt();
+    }
+    void fatalError(const bilingual_str& message) override
+    {
+        m_notifications->fatalError(message);
+        if (m_interrupt_on_fatal_error) (void)m_interrupt();
+    }
+};
+
 class KernelValidationInterface final : public CValidationInterface
 {
 public:
@@ -378,11 +439,57 @@ protected:
     }
 };
 
+//! Records the validation state a single block was checked with, and whether it was
+//! connected to the active chain. Events for the block may be raised by any thread
+//! processing blocks, so the recorded fields are guarded by a mutex.
+class BlockCheckedObserver final : public CValidationInterface
+{
+    mutable Mutex m_mutex;
+    std::optional<BlockValidationState> m_state GUARDED_BY(m_mutex);
+    bool m_connected GUARDED_BY(m_mutex){false};
+
+public:
+    const uint256 m_hash;
+
+    explicit BlockCheckedObserver(const uint256& hash) : m_hash{hash} {}
+
+    std::optional<BlockValidationState> State() const EXCLUSIVE_LOCKS_REQUIRED(!m_mutex)
+    {
+        LOCK(m_mutex);
+        return m_state;
+    }
+
+    bool Connected() const EXCLUSIVE_LOCKS_REQUIRED(!m_mutex)
+    {
+        LOCK(m_mutex);
+        return m_connected;
+    }
+
+protected:
+    void BlockChecked(const std::shared_ptr<const CBlock>& block, const BlockValidationState& stateIn) override
+    {
+        if (block->GetHash() == m_hash) {
+            LOCK(m_mutex);
+            m_state = stateIn;
+        }
+    }
+
+    void BlockConnected(ChainstateRole role, const std::shared_ptr<const CBlock>& block, const CBlockIndex* pindex) override
+    {
+        if (pindex->GetBlockHash() == m_hash) {
+            LOCK(m_mutex);
+            m_connected = true;
+        }
+    }
+};
+
 struct ContextOptions {
     mutable Mutex m_mutex;
     std::unique_ptr<const CChainParams> m_chainparams GUARDED_BY(m_mutex);
     std::shared_ptr<KernelNotifications> m_notifications GUARDED_BY(m_mutex);
     std::shared_ptr<KernelValidationInterface> m_validation_interface GUARDED_BY(m_mutex);
+    bool m_interrupt_on_fatal_error GUARDED_BY(m_mutex){false};
+    bool m_interrupt_on_flush_error GUARDED_BY(m_mutex){false};
 };
 
 class Context
@@ -400,12 +507,19 @@ public:
 
     std::shared_ptr<KernelValidationInterface> m_validation_interface;
 
+    //! Notifications passed to the chainstate manager, wrapping m_notifications
+    std::unique_ptr<ContextNotifications> m_context_notifications;
+
     Context(const ContextOptions* options, bool& sane)
         : m_context{std::make_unique<kernel::Context>()},
           m_interrupt{std::make_unique<util::SignalInterrupt>()}
     {
+        bool interrupt_on_fatal_error{false};
+        bool interrupt_on_flush_error{false};
         if (options) {
             LOCK(options->m_mutex);
+            interrupt_on_fatal_error = options->m_interrupt_on_fatal_error;
+            interrupt_on_flush_error = options->m_interrupt_on_flush_error;
             if (options->m_chainparams) {
                 m_chainparams = std::make_unique<const CChainParams>(*options->m_chainparams);
             }
@@ -413,12 +527,17 @@ public:
                 m_notifications = options->m_notifications;
             }
             if (options->m_validation_interface) {
-                m_signals = std::make_unique<ValidationSignals>(std::make_unique<ImmediateTaskRunner>());
                 m_validation_interface = options->m_validation_interface;
-                m_signals->RegisterSharedValidationInterface(m_validation_interface);
             }
         }
 
+        // Validation signals are always available, so that interfaces can also be
+        // registered internally for the duration of a single call.
+        m_signals = std::make_unique<ValidationSignals>(std::make_unique<ImmediateTaskRunner>());
+        if (m_validation_interface) {
+            m_signals->RegisterSharedValidationInterface(m_validation_interface);
+        }
+
         if (!m_chainparams) {
             m_chainparams = CChainParams::Main();
         }
@@ -426,6 +545,8 @@ public:
             m_notifications = std::make_shared<KernelNotifications>(btck_NotificationInterfaceCallbacks{
                 nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr});
         }
+        m_context_notifications = std::make_unique<ContextNotifications>(m_notifications, *m_interrupt,
+                                                                          interrupt_on_fatal_error, interrupt_on_flush_error);
 
         if (!kernel::SanityChecks(*m_context)) {
             sane = false;
@@ -434,7 +555,7 @@ public:
 
     ~Context()
     {
-        if (m_signals) {
+        if (m_validation_interface) {
             m_signals->UnregisterSharedValidationInterface(m_validation_interface);
         }
     }
@@ -452,12 +573,12 @@ struct ChainstateManagerOptions {
         : m_chainman_options{ChainstateManager::Options{
               .chainparams = *context->m_chainparams,
               .datadir = data_dir,
-              .notifications = *context->m_notifications,
+              .notifications = *context->m_context_notifications,
               .signals = context->m_signals.get()}},
           m_blockman_options{node::BlockManager::Options{
               .chainparams = *context->m_chainparams,
               .blocks_dir = blocks_dir,
-              .notifications = *context->m_notifications,
+              .notifications = *context->m_context_notifications,
               .block_tree_db_params = DBParams{
                   .path = data_dir / "blocks" / "index",
                   .cache_bytes = kernel::CacheSizes{DEFAULT_KERNEL_CACHE}.block_tree_db,
@@ -475,6 +596,16 @@ struct ChainMan {
         : m_chainman(std::move(chainman)), m_context(std::move(context)) {}
 };
 
+btck_DeploymentState get_deployment_state(std::string_view state)
+{
+    if (state == "defined") return btck_DeploymentState_DEFINED;
+    if (state == "started") return btck_DeploymentState_STARTED;
+    if (state == "locked_in") return btck_DeploymentState_LOCKED_IN;
+    if (state == "active") return btck_DeploymentState_ACTIVE;
+    if (state == "failed") return btck_DeploymentState_FAILED;
+    assert(false);
+}
+
 } // namespace
 
 struct btck_Transaction : Handle<btck_Transaction, std::shared_ptr<const CTransaction>> {};
@@ -554,6 +685,74 @@ void btck_transaction_destroy(btck_Transaction* transaction)
     delete transaction;
 }
 
+int btck_transaction_signature_hash(
+    const btck_Transaction* transaction,
+    unsigned int input_index,
+    const btck_ScriptPubkey* script_code,
+    int64_t amount,
+    uint32_t hash_type,
+    btck_SignatureVersion signature_version,
+    const btck_TransactionOutput** spent_outputs_, size_t spent_outputs_len,
+    uint32_t codeseparator_pos,
+    unsigned char output[32])
+{
+    const CTransaction& tx{*btck_Transaction::get(transaction)};
+    assert(input_index < tx.vin.size());
+
+    switch (signature_version) {
+    case btck_SignatureVersion_BASE:
+    case btck_SignatureVersion_WITNESS_V0: {
+        if (script_code == nullptr) return 0;
+        const SigVersion sigversion{signature_version == btck_SignatureVersion_BASE ? SigVersion::BASE : SigVersion::WITNESS_V0};
+        const uint256 hash{SignatureHash(btck_ScriptPubkey::get(script_code), tx, input_index, static_cast<int32_t>(hash_type), amount, sigversion)};
+        std::memcpy(output, hash.begin(), 32);
+        return 1;
+    }
+    case btck_SignatureVersion_TAPROOT:
+    case btck_SignatureVersion_TAPSCRIPT: {
+        if (spent_outputs_ == nullptr || spent_outputs_len != tx.vin.size()) return 0;
+        if (hash_type > 0xff) return 0;
+
+        std::vector<CTxOut> spent_outputs;
+        spent_outputs.reserve(spent_outputs_len);
+        for (size_t i = 0; i < spent_outputs_len; i++) {
+            spent_outputs.push_back(btck_TransactionOutput::get(spent_outputs_[i]));
+        }
+        PrecomputedTransactionData txdata;
+        txdata.Init(tx, std::move(spent_outputs), /*force=*/true);
+
+        ScriptExecutionData execdata;
+        const auto& stack{tx.vin[input_index].scriptWitness.stack};
+        if (stack.size() >= 2 && !stack.back().empty() && stack.back()[0] == ANNEX_TAG) {
+            execdata.m_annex_hash = (HashWriter{} << stack.back()).GetSHA256();
+            execdata.m_annex_present = true;
+        } else {
+            execdata.m_annex_present = false;
+        }
+        execdata.m_annex_init = true;
+
+        SigVersion sigversion{SigVersion::TAPROOT};
+        if (signature_version == btck_SignatureVersion_TAPSCRIPT) {
+            if (script_code == nullptr) return 0;
+            const CScript& leaf_script{btck_ScriptPubkey::get(script_code)};
+            sigversion = SigVersion::TAPSCRIPT;
+            execdata.m_tapleaf_hash = ComputeTapleafHash(TAPROOT_LEAF_TAPSCRIPT, std::span{leaf_script.data(), leaf_script.size()});
+            execdata.m_tapleaf_hash_init = true;
+            execdata.m_codeseparator_pos = codeseparator_pos;
+            execdata.m_codeseparator_pos_init = true;
+        }
+
+        uint256 hash;
+        if (!SignatureHashSchnorr(hash, execdata, tx, input_index, static_cast<uint8_t>(hash_type), sigversion, txdata, MissingDataBehavior::FAIL)) {
+            return 0;
+        }
+        std::memcpy(output, hash.begin(), 32);
+        return 1;
+    }
+    }
+    assert(false);
+}
+
 btck_ScriptPubkey* btck_script_pubkey_create(const void* script_pubkey, size_t script_pubkey_len)
 {
     auto data = std::span{reinterpret_cast<const uint8_t*>(script_pubkey), script_pubkey_len};
@@ -651,6 +850,56 @@ int btck_script_pubkey_verify(const btck_ScriptPubkey* script_pubkey,
     return result ? 1 : 0;
 }
 
+int btck_transaction_verify_scripts(const btck_Transaction* tx_to,
+                                    const btck_TransactionSpentOutputs* transaction_spent_outputs,
+                                    const btck_ScriptVerificationFlags flags,
+                                    int* input_results, size_t input_results_len,
+                                    btck_ScriptVerifyStatus* status)
+{
+    // Assert that all specified flags are part of the interface before continuing
+    assert((flags & ~btck_ScriptVerificationFlags_ALL) == 0);
+
+    if (!is_valid_flag_combination(script_verify_flags::from_int(flags))) {
+        if (status) *status = btck_ScriptVerifyStatus_ERROR_INVALID_FLAGS_COMBINATION;
+        return 0;
+    }
+
+    if (status) *status = btck_ScriptVerifyStatus_OK;
+
+    const CTransaction& tx{*btck_Transaction::get(tx_to)};
+    const CTxUndo& tx_undo{btck_TransactionSpentOutputs::get(transaction_spent_outputs)};
+    assert(tx_undo.vprevout.size() == tx.vin.size());
+    assert(input_results_len == tx.vin.size());
+
+    std::vector<CTxOut> spent_outputs;
+    spent_outputs.reserve(tx_undo.vprevout.size());
+    for (const Coin& coin : tx_undo.vprevout) {
+        spent_outputs.push_back(coin.out);
+    }
+    PrecomputedTransactionData txdata;
+    txdata.Init(tx, std::move(spent_outputs));
+
+    bool all_valid{true};
+    for (size_t i = 0; i < tx.vin.size(); i++) {
+        const CTxOut& spent_output{txdata.m_spent_outputs[i]};
+        bool result = VerifyScript(tx.vin[i].scriptSig,
+                                   spent_output.scriptPubKey,
+                                   &tx.vin[i].scriptWitness,
+                                   script_verify_flags::from_int(flags),
+                                   TransactionSignatureChecker(&tx, i, spent_output.nValue, txdata, MissingDataBehavior::FAIL),
+                                   nullptr);
+        input_results[i] = result ? 1 : 0;
+        all_valid = all_valid && result;
+    }
+    return all_valid ? 1 : 0;
+}
+
+int btck_script_verification_flags_is_valid_combination(const btck_ScriptVerificationFlags flags)
+{
+    assert((flags & ~btck_ScriptVerificationFlags_ALL) == 0);
+    return is_valid_flag_combination(script_verify_flags::from_int(flags)) ? 1 : 0;
+}
+
 btck_TransactionInput* btck_transaction_input_copy(const btck_TransactionInput* input)
 {
     return btck_TransactionInput::copy(input);
@@ -686,6 +935,11 @@ void btck_transaction_out_point_destroy(btck_TransactionOutPoint* out_point)
     delete out_point;
 }
 
+btck_Txid* btck_txid_create(const unsigned char txid[32])
+{
+    return btck_Txid::create(Txid::FromUint256(uint256{std::span<const unsigned char>{txid, 32}}));
+}
+
 btck_Txid* btck_txid_copy(const btck_Txid* txid)
 {
     return btck_Txid::copy(txid);
@@ -782,6 +1036,38 @@ btck_ChainParameters* btck_chain_parameters_copy(const btck_ChainParameters* cha
     return btck_ChainParameters::copy(chain_parameters);
 }
 
+int btck_chain_parameters_get_network_info(const btck_ChainParameters* chain_parameters, btck_NetworkInfo* info)
+{
+    const CChainParams& params{btck_ChainParameters::get(chain_parameters)};
+    *info = btck_NetworkInfo{};
+
+    const auto& hrp{params.Bech32HRP()};
+    const auto& pubkey_prefix{params.Base58Prefix(CChainParams::PUBKEY_ADDRESS)};
+    const auto& script_prefix{params.Base58Prefix(CChainParams::SCRIPT_ADDRESS)};
+    const auto& secret_prefix{params.Base58Prefix(CChainParams::SECRET_KEY)};
+    const auto& ext_public_prefix{params.Base58Prefix(CChainParams::EXT_PUBLIC_KEY)};
+    const auto& ext_secret_prefix{params.Base58Prefix(CChainParams::EXT_SECRET_KEY)};
+    if (hrp.size() > sizeof(info->bech32_hrp) ||
+        pubkey_prefix.size() != 1 || script_prefix.size() != 1 || secret_prefix.size() != 1 ||
+        ext_public_prefix.size() != sizeof(info->ext_public_key_prefix) ||
+        ext_secret_prefix.size() != sizeof(info->ext_secret_key_prefix)) {
+        return -1;
+    }
+
+    std::copy(params.MessageStart().begin(), params.MessageStart().end(), info->message_start);
+    info->default_port = params.GetDefaultPort();
+    std::copy(hrp.begin(), hrp.end(), info->bech32_hrp);
+    info->bech32_hrp_len = hrp.size();
+    info->pubkey_address_prefix = pubkey_prefix[0];
+    info->script_address_prefix = script_prefix[0];
+    info->secret_key_prefix = secret_prefix[0];
+    std::copy(ext_public_prefix.begin(), ext_public_prefix.end(), info->ext_public_key_prefix);
+    std::copy(ext_secret_prefix.begin(), ext_secret_prefix.end(), info->ext_secret_key_prefix);
+    const uint256 genesis_hash{params.GenesisBlock().GetHash()};
+    std::copy(genesis_hash.begin(), genesis_hash.end(), info->genesis_block_hash);
+    return 0;
+}
+
 void btck_chain_parameters_destroy(btck_ChainParameters* chain_parameters)
 {
     delete chain_parameters;
@@ -806,6 +1092,13 @@ void btck_context_options_set_notifications(btck_ContextOptions* options, btck_N
     btck_ContextOptions::get(options).m_notifications = std::make_shared<KernelNotifications>(notifications);
 }
 
+void btck_context_options_set_interrupt_on_error(btck_ContextOptions* options, int fatal_error, int flush_error)
+{
+    LOCK(btck_ContextOptions::get(options).m_mutex);
+    btck_ContextOptions::get(options).m_interrupt_on_fatal_error = fatal_error != 0;
+    btck_ContextOptions::get(options).m_interrupt_on_flush_error = flush_error != 0;
+}
+
 void btck_context_options_set_validation_interface(btck_ContextOptions* options, btck_ValidationInterfaceCallbacks vi_cbs)
 {
     LOCK(btck_ContextOptions::get(options).m_mutex);
@@ -839,6 +1132,11 @@ int btck_context_interrupt(btck_Context* context)
     return (*btck_Context::get(context)->m_interrupt)() ? 0 : -1;
 }
 
+int btck_context_reset_interrupt(btck_Context* context)
+{
+    return btck_Context::get(context)->m_interrupt->reset() ? 0 : -1;
+}
+
 void btck_context_destroy(btck_Context* context)
 {
     delete context;
@@ -998,6 +1296,108 @@ const btck_BlockTreeEntry* btck_chainstate_manager_get_block_tree_entry_by_hash(
     return btck_BlockTreeEntry::ref(block_index);
 }
 
+btck_ScriptVerificationFlags btck_chainstate_manager_get_block_script_flags(const btck_ChainstateManager* chainman, const btck_BlockTreeEntry* entry)
+{
+    const auto flags{GetBlockScriptFlags(btck_BlockTreeEntry::get(entry), *btck_ChainstateManager::get(chainman).m_chainman)};
+    // All flags set by consensus are part of the interface
+    assert((flags.as_int() & ~btck_ScriptVerificationFlags_ALL) == 0);
+    return static_cast<btck_ScriptVerificationFlags>(flags.as_int());
+}
+
+int btck_chainstate_manager_get_deployment_info(const btck_ChainstateManager* chainman_, const btck_BlockTreeEntry* entry_, btck_Deployment deployment, btck_DeploymentInfo* info)
+{
+    const ChainstateManager& chainman{*btck_ChainstateManager::get(chainman_).m_chainman};
+    const CBlockIndex& entry{btck_BlockTreeEntry::get(entry_)};
+    *info = btck_DeploymentInfo{};
+    info->height = -1;
+
+    const auto buried_info{[&](Consensus::BuriedDeployment dep) {
+        if (!DeploymentEnabled(chainman, dep)) return -1;
+        info->buried = 1;
+        info->active = DeploymentActiveAfter(&entry, chainman, dep) ? 1 : 0;
+        info->height = chainman.GetConsensus().DeploymentHeight(dep);
+        return 0;
+    }};
+    const auto versionbits_info{[&](Consensus::DeploymentPos pos) {
+        if (!DeploymentEnabled(chainman, pos)) return -1;
+        const BIP9Info bip9{chainman.m_versionbitscache.Info(entry, chainman.GetConsensus(), pos)};
+        const auto& params{chainman.GetConsensus().vDeployments[pos]};
+        info->buried = 0;
+        info->bit = params.bit;
+        info->start_time = params.nStartTime;
+        info->timeout = params.nTimeout;
+        info->min_activation_height = params.min_activation_height;
+        info->state = get_deployment_state(bip9.current_state);
+        info->state_next = get_deployment_state(bip9.next_state);
+        info->since = bip9.since;
+        if (bip9.active_since.has_value()) {
+            info->height = *bip9.active_since;
+            info->active = *bip9.active_since <= entry.nHeight + 1 ? 1 : 0;
+        }
+        if (bip9.stats.has_value()) {
+            info->has_statistics = 1;
+            info->period = bip9.stats->period;
+            info->threshold = bip9.stats->threshold;
+            info->elapsed = bip9.stats->elapsed;
+            info->count = bip9.stats->count;
+            info->possible = bip9.stats->possible ? 1 : 0;
+        }
+        return 0;
+    }};
+
+    switch (deployment) {
+    case btck_Deployment_HEIGHTINCB:
+        return buried_info(Consensus::DEPLOYMENT_HEIGHTINCB);
+    case btck_Deployment_DERSIG:
+        return buried_info(Consensus::DEPLOYMENT_DERSIG);
+    case btck_Deployment_CLTV:
+        return buried_info(Consensus::DEPLOYMENT_CLTV);
+    case btck_Deployment_CSV:
+        return buried_info(Consensus::DEPLOYMENT_CSV);
+    case btck_Deployment_SEGWIT:
+        return buried_info(Consensus::DEPLOYMENT_SEGWIT);
+    case btck_Deployment_TESTDUMMY:
+        return versionbits_info(Consensus::DEPLOYMENT_TESTDUMMY);
+    case btck_Deployment_TAPROOT:
+        return versionbits_info(Consensus::DEPLOYMENT_TAPROOT);
+    }
+    assert(false);
+}
+
+uint32_t btck_chainstate_manager_get_next_work_required(const btck_ChainstateManager* chainman, const btck_BlockTreeEntry* entry, int64_t block_time)
+{
+    CBlockHeader header;
+    header.nTime = static_cast<uint32_t>(block_time);
+    return GetNextWorkRequired(&btck_BlockTreeEntry::get(entry), &header, btck_ChainstateManager::get(chainman).m_chainman->GetConsensus());
+}
+
+int btck_chainstate_manager_have_pruned(const btck_ChainstateManager* chainman)
+{
+    LOCK(::cs_main);
+    return btck_ChainstateManager::get(chainman).m_chainman->m_blockman.m_have_pruned ? 1 : 0;
+}
+
+int btck_chainstate_manager_snapshot_active_chain(const btck_ChainstateManager* chainman, btck_WriteBytes writer, void* user_data)
+{
+    try {
+        auto& chainman_{*btck_ChainstateManager::get(chainman).m_chainman};
+        DataStream snapshot{};
+        {
+            LOCK(chainman_.GetMutex());
+            const CChain& chain{chainman_.ActiveChain()};
+            const CBlockIndex* tip{chain.Tip()};
+            snapshot << int32_t{chain.Height()};
+            snapshot << (tip ? ArithToUint256(tip->nChainWork) : uint256{});
+            for (int height{0}; height <= chain.Height(); ++height) {
+                snapshot << chain[height]->GetBlockHash();
+            }
+        }
+        return writer(snapshot.data(), snapshot.size(), user_data);
+    } catch (...) {
+        return -1;
+    }
+}
+
 void btck_chainstate_manager_destroy(btck_ChainstateManager* chainman)
 {
     {
@@ -1104,6 +1504,18 @@ const btck_BlockHash* btck_block_tree_entry_get_block_hash(const btck_BlockTreeE
     return btck_BlockHash::ref(btck_BlockTreeEntry::get(entry).phashBlock);
 }
 
+int btck_block_tree_entry_has_block_data(const btck_BlockTreeEntry* entry)
+{
+    LOCK(::cs_main);
+    return (btck_BlockTreeEntry::get(entry).nStatus & BLOCK_HAVE_DATA) ? 1 : 0;
+}
+
+int btck_block_tree_entry_has_undo_data(const btck_BlockTreeEntry* entry)
+{
+    LOCK(::cs_main);
+    return (btck_BlockTreeEntry::get(entry).nStatus & BLOCK_HAVE_UNDO) ? 1 : 0;
+}
+
 btck_BlockHash* btck_block_hash_create(const unsigned char block_hash[32])
 {
     return btck_BlockHash::create(std::span<const unsigned char>{block_hash, 32});
@@ -1143,6 +1555,22 @@ btck_BlockSpentOutputs* btck_block_spent_outputs_read(const btck_ChainstateManag
     return btck_BlockSpentOutputs::create(block_undo);
 }
 
+btck_BlockSpentOutputs* btck_block_spent_outputs_create(const void* raw_block_spent_outputs, size_t raw_block_spent_outputs_len)
+{
+    auto block_undo{std::make_shared<CBlockUndo>()};
+
+    DataStream stream{std::span{reinterpret_cast<const std::byte*>(raw_block_spent_outputs), raw_block_spent_outputs_len}};
+
+    try {
+        stream >> *block_undo;
+    } catch (...) {
+        LogDebug(BCLog::KERNEL, "Block spent outputs decode failed.");
+        return nullptr;
+    }
+
+    return btck_BlockSpentOutputs::create(block_undo);
+}
+
 btck_BlockSpentOutputs* btck_block_spent_outputs_copy(const btck_BlockSpentOutputs* block_spent_outputs)
 {
     return btck_BlockSpentOutputs::copy(block_spent_outputs);
@@ -1160,6 +1588,17 @@ const btck_TransactionSpentOutputs* btck_block_spent_outputs_get_transaction_spe
     return btck_TransactionSpentOutputs::ref(tx_undo);
 }
 
+int btck_block_spent_outputs_to_bytes(const btck_BlockSpentOutputs* block_spent_outputs, btck_WriteBytes writer, void* user_data)
+{
+    try {
+        WriterStream ws{writer, user_data};
+        ws << *btck_BlockSpentOutputs::get(block_spent_outputs);
+        return 0;
+    } catch (...) {
+        return -1;
+    }
+}
+
 void btck_block_spent_outputs_destroy(btck_BlockSpentOutputs* block_spent_outputs)
 {
     delete block_spent_outputs;
@@ -1225,6 +1664,38 @@ int btck_chainstate_manager_process_block(
     return result ? 0 : -1;
 }
 
+int btck_chainstate_manager_process_block_with_result(
+    btck_ChainstateManager* chainman,
+    const btck_Block* block,
+    int* _new_block,
+    int* checked,
+    btck_ValidationMode* validation_mode,
+    btck_BlockValidationResult* validation_result,
+    int* connected)
+{
+    auto& chainstate_manager{btck_ChainstateManager::get(chainman)};
+    const auto& shared_block{btck_Block::get(block)};
+    auto observer{std::make_shared<BlockCheckedObserver>(shared_block->GetHash())};
+    auto& signals{*chainstate_manager.m_context->m_signals};
+
+    signals.RegisterSharedValidationInterface(observer);
+    bool new_block;
+    auto result = chainstate_manager.m_chainman->ProcessNewBlock(shared_block, /*force_processing=*/true, /*min_pow_checked=*/true, /*new_block=*/&new_block);
+    signals.UnregisterSharedValidationInterface(observer);
+
+    if (_new_block) {
+        *_new_block = new_block ? 1 : 0;
+    }
+    const auto state{observer->State()};
+    *checked = state ? 1 : 0;
+    if (state) {
+        *validation_mode = btck_block_validation_state_get_validation_mode(btck_BlockValidationState::ref(&*state));
+        *validation_result = btck_block_validation_state_get_block_validation_result(btck_BlockValidationState::ref(&*state));
+    }
+    *connected = observer->Connected() ? 1 : 0;
+    return result ? 0 : -1;
+}
+
 const btck_Chain* btck_chainstate_manager_get_active_chain(const btck_ChainstateManager* chainman)
 {
     return btck_Chain::ref(&WITH_LOCK(btck_ChainstateManager::get(chainman).m_chainman->GetMutex(), return btck_ChainstateManager::get(chainman).m_chainman->ActiveChain()));
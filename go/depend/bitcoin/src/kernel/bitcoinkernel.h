@@ -454,6 +454,62 @@ typedef uint32_t btck_ScriptVerificationFlags;
                                                                          btck_ScriptVerificationFlags_WITNESS |             \
                                                                          btck_ScriptVerificationFlags_TAPROOT))
 
+/**
+ * The signature hashing scheme used to compute a transaction's signature hash.
+ */
+typedef uint8_t btck_SignatureVersion;
+#define btck_SignatureVersion_BASE ((btck_SignatureVersion)(0))       //!< Bare scripts and BIP16 P2SH-wrapped redeemscripts
+#define btck_SignatureVersion_WITNESS_V0 ((btck_SignatureVersion)(1)) //!< Witness v0 (P2WPKH and P2WSH), see BIP143
+#define btck_SignatureVersion_TAPROOT ((btck_SignatureVersion)(2))    //!< Witness v1 key path spending, see BIP341
+#define btck_SignatureVersion_TAPSCRIPT ((btck_SignatureVersion)(3))  //!< Witness v1 script path spending with leaf version 0xc0, see BIP342
+
+/**
+ * A consensus rule change deployment. The first five are buried deployments
+ * enforced from a fixed height, the others are BIP9 versionbits deployments.
+ */
+typedef uint8_t btck_Deployment;
+#define btck_Deployment_HEIGHTINCB ((btck_Deployment)(0)) //!< BIP34, block height in coinbase
+#define btck_Deployment_DERSIG ((btck_Deployment)(1))     //!< BIP66, strict DER signatures
+#define btck_Deployment_CLTV ((btck_Deployment)(2))       //!< BIP65, CHECKLOCKTIMEVERIFY
+#define btck_Deployment_CSV ((btck_Deployment)(3))        //!< BIPs 68, 112 & 113, relative lock-times
+#define btck_Deployment_SEGWIT ((btck_Deployment)(4))     //!< BIPs 141, 143 & 147, segregated witness
+#define btck_Deployment_TESTDUMMY ((btck_Deployment)(5))  //!< Dummy versionbits deployment used for testing
+#define btck_Deployment_TAPROOT ((btck_Deployment)(6))    //!< BIPs 340-342, schnorr signatures and taproot
+
+/**
+ * The BIP9 state of a versionbits deployment.
+ */
+typedef uint8_t btck_DeploymentState;
+#define btck_DeploymentState_DEFINED ((btck_DeploymentState)(0))   //!< First state that each softfork starts out as
+#define btck_DeploymentState_STARTED ((btck_DeploymentState)(1))   //!< Signalling for the deployment has started
+#define btck_DeploymentState_LOCKED_IN ((btck_DeploymentState)(2)) //!< The signalling threshold was reached in the previous period
+#define btck_DeploymentState_ACTIVE ((btck_DeploymentState)(3))    //!< The deployment is enforced, this is a final state
+#define btck_DeploymentState_FAILED ((btck_DeploymentState)(4))    //!< The deployment timed out without activating, this is a final state
+
+/**
+ * Describes the state of a deployment at a block, mirroring the
+ * getdeploymentinfo RPC. The versionbits fields are only set if buried is 0,
+ * and the signalling statistics only if has_statistics is non-zero.
+ */
+typedef struct {
+    int buried;                      //!< Non-zero for buried deployments, 0 for versionbits deployments.
+    int active;                      //!< Non-zero if the rules are enforced for the block following the queried block.
+    int32_t height;                  //!< Height from which the rules are enforced, or -1 if not known yet.
+    int bit;                         //!< The version bit used for signalling.
+    int64_t start_time;              //!< Median time past from which signalling starts.
+    int64_t timeout;                 //!< Median time past at which the deployment fails if not locked in.
+    int32_t min_activation_height;   //!< Minimum height at which the deployment can become active.
+    btck_DeploymentState state;      //!< The state of the deployment for the queried block.
+    btck_DeploymentState state_next; //!< The state of the deployment for the block following the queried block.
+    int32_t since;                   //!< Height of the first block to which the current state applies.
+    int has_statistics;              //!< Non-zero if signalling statistics are available for the current period.
+    uint32_t period;                 //!< Length of the signalling period in blocks.
+    uint32_t threshold;              //!< Number of signalling blocks required for locking in, 0 if already locked in.
+    uint32_t elapsed;                //!< Number of blocks elapsed since the beginning of the current period.
+    uint32_t count;                  //!< Number of signalling blocks since the beginning of the current period.
+    int possible;                    //!< Non-zero if the threshold can still be reached in the current period.
+} btck_DeploymentInfo;
+
 typedef uint8_t btck_ChainType;
 #define btck_ChainType_MAINNET ((btck_ChainType)(0))
 #define btck_ChainType_TESTNET ((btck_ChainType)(1))
@@ -461,6 +517,23 @@ typedef uint8_t btck_ChainType;
 #define btck_ChainType_SIGNET ((btck_ChainType)(3))
 #define btck_ChainType_REGTEST ((btck_ChainType)(4))
 
+/**
+ * Describes the network a set of chain parameters belongs to: how its peers
+ * identify each other and how its addresses and keys are encoded.
+ */
+typedef struct {
+    unsigned char message_start[4];         //!< Magic bytes starting every P2P message.
+    uint16_t default_port;                  //!< Default port of the P2P network.
+    char bech32_hrp[16];                    //!< Human readable part of bech32 addresses, not null-terminated.
+    size_t bech32_hrp_len;                  //!< Length of the human readable part.
+    unsigned char pubkey_address_prefix;    //!< Base58 version byte of pay-to-pubkey-hash addresses.
+    unsigned char script_address_prefix;    //!< Base58 version byte of pay-to-script-hash addresses.
+    unsigned char secret_key_prefix;        //!< Base58 version byte of WIF private keys.
+    unsigned char ext_public_key_prefix[4]; //!< Base58 version bytes of BIP32 extended public keys.
+    unsigned char ext_secret_key_prefix[4]; //!< Base58 version bytes of BIP32 extended private keys.
+    unsigned char genesis_block_hash[32];   //!< Hash of the genesis block.
+} btck_NetworkInfo;
+
 /** @name Transaction
  * Functions for working with transactions.
  */
@@ -553,6 +626,48 @@ BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_count
 BITCOINKERNEL_API const btck_Txid* BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_get_txid(
     const btck_Transaction* transaction) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Compute the signature hash of the input at input_index of the
+ * transaction, as used by the consensus signature checks. The hashing scheme
+ * is selected through signature_version.
+ *
+ * For btck_SignatureVersion_BASE and btck_SignatureVersion_WITNESS_V0 the
+ * script code is the script being executed (e.g. the redeem script or the
+ * witness script) and the amount is only committed to by the witness v0
+ * scheme. For btck_SignatureVersion_TAPSCRIPT the script code is the executed
+ * tapleaf script, for btck_SignatureVersion_TAPROOT it is ignored. Both
+ * taproot schemes require the full set of spent outputs. The annex, if present,
+ * is taken from the input's witness.
+ *
+ * For btck_SignatureVersion_TAPSCRIPT, codeseparator_pos is the opcode position
+ * of the last OP_CODESEPARATOR executed before the signature check (BIP342), or
+ * 0xFFFFFFFF if none was executed. It is ignored by the other schemes.
+ *
+ * @param[in] transaction       Non-null.
+ * @param[in] input_index       Index of the input for which the hash is computed.
+ * @param[in] script_code       Nullable for btck_SignatureVersion_TAPROOT, the script code committed to.
+ * @param[in] amount            Amount of the output spent by the input.
+ * @param[in] hash_type         The sighash type, e.g. 1 for SIGHASH_ALL.
+ * @param[in] signature_version The signature hashing scheme.
+ * @param[in] spent_outputs     Nullable unless a taproot scheme is selected. Points to an array of
+ *                              outputs spent by the transaction.
+ * @param[in] spent_outputs_len Length of the spent_outputs array.
+ * @param[in] codeseparator_pos Position of the last executed OP_CODESEPARATOR, only used by tapscript.
+ * @param[out] output           The computed 32 byte signature hash.
+ * @return                      1 on success, 0 if the hash could not be computed with the
+ *                              provided arguments.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_signature_hash(
+    const btck_Transaction* transaction,
+    unsigned int input_index,
+    const btck_ScriptPubkey* script_code,
+    int64_t amount,
+    uint32_t hash_type,
+    btck_SignatureVersion signature_version,
+    const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len,
+    uint32_t codeseparator_pos,
+    unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 10);
+
 /**
  * Destroy the transaction.
  */
@@ -611,6 +726,38 @@ BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_pubkey_verify
     btck_ScriptVerificationFlags flags,
     btck_ScriptVerifyStatus* status) BITCOINKERNEL_ARG_NONNULL(1, 3);
 
+/**
+ * @brief Verify the scripts of all inputs of tx_to against the outputs they spend
+ * under the constraints specified by flags. The transaction data shared by the
+ * signature hashes of the inputs is only computed once, so this is faster than
+ * calling btck_script_pubkey_verify for every input.
+ *
+ * @param[in] tx_to                     Non-null, transaction whose inputs are verified.
+ * @param[in] transaction_spent_outputs Non-null, outputs spent by the inputs of tx_to, e.g.
+ *                                      from the undo data of the block containing it.
+ * @param[in] flags                     Bitfield of btck_ScriptVerificationFlags controlling validation constraints.
+ * @param[out] input_results            Non-null, set to 1 for each input whose script is valid, 0 otherwise.
+ * @param[in] input_results_len         Length of the input_results array, must be the number of inputs of tx_to.
+ * @param[out] status                   Nullable, will be set to an error code if the operation fails, or OK otherwise.
+ * @return                              1 if the scripts of all inputs are valid, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_verify_scripts(
+    const btck_Transaction* tx_to,
+    const btck_TransactionSpentOutputs* transaction_spent_outputs,
+    btck_ScriptVerificationFlags flags,
+    int* input_results, size_t input_results_len,
+    btck_ScriptVerifyStatus* status) BITCOINKERNEL_ARG_NONNULL(1, 2, 4);
+
+/**
+ * @brief Check whether the script verification flags are combined in a way the
+ * verification functions accept.
+ *
+ * @param[in] flags Bitfield of btck_ScriptVerificationFlags.
+ * @return          1 if the combination is valid, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_verification_flags_is_valid_combination(
+    btck_ScriptVerificationFlags flags);
+
 /**
  * @brief Serializes the script pubkey through the passed in callback to bytes.
  *
@@ -789,6 +936,18 @@ BITCOINKERNEL_API btck_ChainParameters* BITCOINKERNEL_WARN_UNUSED_RESULT btck_ch
 BITCOINKERNEL_API btck_ChainParameters* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_parameters_copy(
     const btck_ChainParameters* chain_parameters) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Retrieves the network metadata of the chain parameters.
+ *
+ * @param[in] chain_parameters Non-null.
+ * @param[out] info            Non-null, filled with the network metadata.
+ * @return                     0 on success, -1 if the metadata does not fit
+ *                             into btck_NetworkInfo.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_parameters_get_network_info(
+    const btck_ChainParameters* chain_parameters,
+    btck_NetworkInfo* info) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * Destroy the chain parameters.
  */
@@ -828,6 +987,21 @@ BITCOINKERNEL_API void btck_context_options_set_notifications(
     btck_ContextOptions* context_options,
     btck_NotificationInterfaceCallbacks notifications) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Make contexts created with the options interrupt themselves, like
+ * @ref btck_context_interrupt, when the kernel reports a fatal error or a
+ * failure to flush the chainstate. The notification callbacks are called
+ * before the context is interrupted.
+ *
+ * @param[in] context_options Non-null, previously created by @ref btck_context_options_create.
+ * @param[in] fatal_error     Whether a fatal error interrupts the context.
+ * @param[in] flush_error     Whether a flush error interrupts the context.
+ */
+BITCOINKERNEL_API void btck_context_options_set_interrupt_on_error(
+    btck_ContextOptions* context_options,
+    int fatal_error,
+    int flush_error) BITCOINKERNEL_ARG_NONNULL(1);
+
 /**
  * @brief Set the validation interface callbacks for the context options. The
  * context created with the options will be configured for these validation
@@ -882,6 +1056,17 @@ BITCOINKERNEL_API btck_Context* BITCOINKERNEL_WARN_UNUSED_RESULT btck_context_co
 BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_context_interrupt(
     btck_Context* context) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Clears a previously triggered interrupt, so that long-running
+ * validation functions can be run again with objects created from this
+ * context.
+ *
+ * @param[in] context  Non-null.
+ * @return             0 if the interrupt was reset successfully, non-zero otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_context_reset_interrupt(
+    btck_Context* context) BITCOINKERNEL_ARG_NONNULL(1);
+
 /**
  * Destroy the context.
  */
@@ -922,6 +1107,27 @@ BITCOINKERNEL_API int32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry
 BITCOINKERNEL_API const btck_BlockHash* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_get_block_hash(
     const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Returns whether the full block data of a block tree entry is stored on
+ * disk. This is not the case for entries only known from their header, or whose
+ * block data has been pruned.
+ *
+ * @param[in] block_tree_entry Non-null.
+ * @return                     1 if the block data is available, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_has_block_data(
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns whether the undo data of a block tree entry is stored on disk.
+ * Undo data is only written once a block has been connected.
+ *
+ * @param[in] block_tree_entry Non-null.
+ * @return                     1 if the undo data is available, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_has_undo_data(
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
 ///@}
 
 /** @name ChainstateManagerOptions
@@ -1055,6 +1261,33 @@ BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_p
     const btck_Block* block,
     int* new_block) BITCOINKERNEL_ARG_NONNULL(1, 2, 3);
 
+/**
+ * @brief Process and validate the passed in block like
+ * btck_chainstate_manager_process_block, and report the result of the block's
+ * validation. The result is taken from the block_checked validation event issued
+ * for this block while it was processed, which does not happen if the block was
+ * already known, or was stored without being connected to the best chain.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block              Non-null, block to be validated.
+ *
+ * @param[out] new_block         Nullable, will be set to 1 if this block was not processed before.
+ * @param[out] checked           Non-null, will be set to 1 if the block was checked while it was processed, and 0 otherwise.
+ * @param[out] validation_mode   Non-null, will be set to the validation mode of the block if it was checked.
+ * @param[out] validation_result Non-null, will be set to the validation result of the block if it was checked.
+ * @param[out] connected         Non-null, will be set to 1 if the block was connected to the active chain while it was
+ *                               processed, moving the tip of the active chain, and 0 otherwise.
+ * @return                       0 if processing the block was successful. Will also return 0 for valid, but duplicate blocks.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_process_block_with_result(
+    btck_ChainstateManager* chainstate_manager,
+    const btck_Block* block,
+    int* new_block,
+    int* checked,
+    btck_ValidationMode* validation_mode,
+    btck_BlockValidationResult* validation_result,
+    int* connected) BITCOINKERNEL_ARG_NONNULL(1, 2, 4, 5, 6, 7);
+
 /**
  * @brief Returns the best known currently active chain. Its lifetime is
  * dependent on the chainstate manager. It can be thought of as a view on a
@@ -1084,6 +1317,87 @@ BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT bt
     const btck_ChainstateManager* chainstate_manager,
     const btck_BlockHash* block_hash) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
+/**
+ * @brief Get the script verification flags that are enforced by consensus
+ * for the scripts of the block the block tree entry points to. This takes
+ * the buried deployment heights and the historical script flag exceptions of
+ * the chain parameters into account.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block_tree_entry   Non-null.
+ * @return                       Bitfield of btck_ScriptVerificationFlags.
+ */
+BITCOINKERNEL_API btck_ScriptVerificationFlags BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_block_script_flags(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Get the state of a deployment at the block the block tree entry
+ * points to. The reported state follows the semantics of the
+ * getdeploymentinfo RPC, i.e. a deployment is reported as active if its rules
+ * are enforced for the block following the entry.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block_tree_entry   Non-null.
+ * @param[in] deployment         The deployment to query.
+ * @param[out] info              Non-null, will be filled with the deployment's state.
+ * @return                       0 on success, -1 if the deployment is not enabled on the
+ *                               configured chain.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_deployment_info(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_BlockTreeEntry* block_tree_entry,
+    btck_Deployment deployment,
+    btck_DeploymentInfo* info) BITCOINKERNEL_ARG_NONNULL(1, 2, 4);
+
+/**
+ * @brief Calculate the proof of work target, in compact form, that a block
+ * building on the block the block tree entry points to is required to have.
+ * Takes the difficulty adjustment rules of the configured chain into account.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block_tree_entry   Non-null, the previous block.
+ * @param[in] block_time         The timestamp of the new block header. Only
+ *                               relevant on chains that allow minimum
+ *                               difficulty blocks.
+ * @return                       The required target in compact form (nBits).
+ */
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_next_work_required(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_BlockTreeEntry* block_tree_entry,
+    int64_t block_time) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Returns whether any block files of the chainstate manager have ever
+ * been pruned.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       1 if block files have been pruned, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_have_pruned(
+    const btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Serializes a consistent snapshot of the active chain. The chain is
+ * read while holding the chainstate manager's lock, so it cannot change while
+ * the snapshot is taken. The writer is called once the lock is released.
+ *
+ * The snapshot consists of the height of the tip as a little-endian int32_t
+ * (-1 if the chain is empty), the total chain work up to and including the tip
+ * as a little-endian 256 bit integer, and the hashes of all blocks from the
+ * genesis block to the tip, 32 bytes each.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] writer             Non-null, callback receiving the serialized snapshot.
+ * @param[in] user_data          Holds a user-defined opaque structure that will be
+ *                               passed back through the writer callback.
+ * @return                       0 on success.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_snapshot_active_chain(
+    const btck_ChainstateManager* chainstate_manager,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * Destroy the chainstate manager.
  */
@@ -1275,6 +1589,17 @@ BITCOINKERNEL_API btck_BlockSpentOutputs* BITCOINKERNEL_WARN_UNUSED_RESULT btck_
     const btck_ChainstateManager* chainstate_manager,
     const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
+/**
+ * @brief Parse serialized block spent outputs, in the format used by the undo
+ * data of the rev*.dat files, into a new block spent outputs object.
+ *
+ * @param[in] raw_block_spent_outputs     Non-null, serialized block spent outputs.
+ * @param[in] raw_block_spent_outputs_len Length of the serialized block spent outputs.
+ * @return                                The allocated block spent outputs, or null on error.
+ */
+BITCOINKERNEL_API btck_BlockSpentOutputs* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_spent_outputs_create(
+    const void* raw_block_spent_outputs, size_t raw_block_spent_outputs_len) BITCOINKERNEL_ARG_NONNULL(1);
+
 /**
  * @brief Copy a block's spent outputs.
  *
@@ -1307,6 +1632,21 @@ BITCOINKERNEL_API const btck_TransactionSpentOutputs* BITCOINKERNEL_WARN_UNUSED_
     const btck_BlockSpentOutputs* block_spent_outputs,
     size_t transaction_spent_outputs_index) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Serializes the block spent outputs through the passed in callback to
+ * bytes. This is the undo data serialization used by the rev*.dat files.
+ *
+ * @param[in] block_spent_outputs Non-null.
+ * @param[in] writer              Non-null, callback to a write bytes function.
+ * @param[in] user_data           Holds a user-defined opaque structure that will be
+ *                                passed back through the writer callback.
+ * @return                        0 on success.
+ */
+BITCOINKERNEL_API int btck_block_spent_outputs_to_bytes(
+    const btck_BlockSpentOutputs* block_spent_outputs,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * Destroy the block spent outputs.
  */
@@ -1435,6 +1775,15 @@ BITCOINKERNEL_API void btck_transaction_out_point_destroy(btck_TransactionOutPoi
  */
 ///@{
 
+/**
+ * @brief Create a txid from its raw data.
+ *
+ * @param[in] txid Non-null, 32 bytes of txid data in internal byte order.
+ * @return         The txid.
+ */
+BITCOINKERNEL_API btck_Txid* BITCOINKERNEL_WARN_UNUSED_RESULT btck_txid_create(
+    const unsigned char txid[32]) BITCOINKERNEL_ARG_NONNULL(1);
+
 /**
  * @brief Copy a txid.
  *
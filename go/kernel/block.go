@@ -5,6 +5,8 @@ package kernel
 */
 import "C"
 import (
+	"io"
+	"iter"
 	"unsafe"
 )
 
@@ -34,13 +36,37 @@ func newBlock(ptr *C.btck_Block, fromOwned bool) *Block {
 //
 // Returns an error if the block data is malformed or cannot be parsed.
 func NewBlock(rawBlock []byte) (*Block, error) {
+	if len(rawBlock) == 0 {
+		return nil, &Error{Op: "create block", Code: ErrorCodeDeserialization}
+	}
 	ptr := C.btck_block_create(unsafe.Pointer(&rawBlock[0]), C.size_t(len(rawBlock)))
 	if ptr == nil {
-		return nil, &InternalError{"Failed to create block from bytes"}
+		return nil, &Error{Op: "create block", Code: ErrorCodeDeserialization}
 	}
 	return newBlock(ptr, true), nil
 }
 
+// ReadBlock reads a single block in consensus serialization from r. It reads no
+// further than the end of the block, so consecutive blocks can be read from the same
+// stream.
+//
+// Parameters:
+//   - r: Stream positioned at the start of a serialized block
+//
+// Returns io.EOF if r is at its end before the block starts, or an error with code
+// ErrorCodeDeserialization if the block is truncated or malformed.
+func ReadBlock(r io.Reader) (*Block, error) {
+	d := newStreamDecodeReader(r)
+	d.block()
+	if d.err == io.EOF {
+		return nil, io.EOF
+	}
+	if d.err != nil {
+		return nil, &Error{Op: "read block", Code: ErrorCodeDeserialization, Err: d.err}
+	}
+	return NewBlock(d.raw)
+}
+
 // Hash calculates and returns the hash of this block.
 func (b *Block) Hash() *BlockHash {
 	return newBlockHash(C.btck_block_get_hash((*C.btck_Block)(b.ptr)), true)
@@ -54,11 +80,24 @@ func (b *Block) Bytes() ([]byte, error) {
 		return C.btck_block_to_bytes((*C.btck_Block)(b.ptr), writer, userData)
 	})
 	if !ok {
-		return nil, &SerializationError{"Failed to serialize block"}
+		return nil, &Error{Op: "serialize block", Code: ErrorCodeSerialization}
 	}
 	return bytes, nil
 }
 
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same data as Bytes.
+func (b *Block) MarshalBinary() ([]byte, error) {
+	return b.Bytes()
+}
+
+// WriteTo implements io.WriterTo, streaming the consensus serialization of the block
+// to w without buffering it.
+func (b *Block) WriteTo(w io.Writer) (int64, error) {
+	return writeToWriter(w, "serialize block", func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_block_to_bytes((*C.btck_Block)(b.ptr), writer, userData)
+	})
+}
+
 // Copy creates a shallow copy of the block by incrementing its reference count.
 //
 // Blocks are reference-counted internally,
@@ -79,11 +118,27 @@ func (b *Block) CountTransactions() uint64 {
 // Parameters:
 //   - index: Index of the transaction to retrieve
 //
-// Returns an error if the index is out of bounds.
+// Returns an error with code ErrorCodeInvalidArgument if the index is out of bounds.
 func (b *Block) GetTransactionAt(index uint64) (*TransactionView, error) {
 	if index >= b.CountTransactions() {
-		return nil, ErrKernelIndexOutOfBounds
+		return nil, &Error{Op: "get transaction", Code: ErrorCodeInvalidArgument, Err: ErrKernelIndexOutOfBounds}
 	}
 	ptr := C.btck_block_get_transaction_at((*C.btck_Block)(b.ptr), C.size_t(index))
-	return newTransactionView(check(ptr)), nil
+	return newTransactionView(check(ptr), b.handle), nil
+}
+
+// Transactions returns an iterator over the transactions of the block, yielding
+// each transaction's index together with the transaction.
+//
+// The yielded transactions are non-owned views that depend on the lifetime of this Block.
+func (b *Block) Transactions() iter.Seq2[uint64, *TransactionView] {
+	return func(yield func(uint64, *TransactionView) bool) {
+		count := b.CountTransactions()
+		for i := uint64(0); i < count; i++ {
+			ptr := C.btck_block_get_transaction_at((*C.btck_Block)(b.ptr), C.size_t(i))
+			if !yield(i, newTransactionView(check(ptr), b.handle)) {
+				return
+			}
+		}
+	}
 }
@@ -5,6 +5,7 @@ package kernel
 */
 import "C"
 import (
+	"encoding/json"
 	"unsafe"
 )
 
@@ -26,7 +27,7 @@ type BlockHash struct {
 
 func newBlockHash(ptr *C.btck_BlockHash, fromOwned bool) *BlockHash {
 	h := newHandle(unsafe.Pointer(ptr), blockHashCFuncs{}, fromOwned)
-	return &BlockHash{handle: h, blockHashApi: blockHashApi{(*C.btck_BlockHash)(h.ptr)}}
+	return &BlockHash{handle: h, blockHashApi: blockHashApi{(*C.btck_BlockHash)(h.ptr), h}}
 }
 
 // BlockHashView is a type-safe identifier for a block.
@@ -35,26 +36,47 @@ type BlockHashView struct {
 	ptr *C.btck_BlockHash
 }
 
-func newBlockHashView(ptr *C.btck_BlockHash) *BlockHashView {
+func newBlockHashView(ptr *C.btck_BlockHash, owner viewOwner) *BlockHashView {
 	return &BlockHashView{
-		blockHashApi: blockHashApi{ptr},
+		blockHashApi: blockHashApi{ptr, owner},
 		ptr:          ptr,
 	}
 }
 
 type blockHashApi struct {
-	ptr *C.btck_BlockHash
+	ptr   *C.btck_BlockHash
+	owner viewOwner
 }
 
-func (bh *blockHashApi) blockHashPtr() *C.btck_BlockHash {
+func (bh *blockHashApi) cptr() *C.btck_BlockHash {
+	checkOwner(bh.owner)
 	return bh.ptr
 }
 
+func (bh *blockHashApi) blockHashPtr() *C.btck_BlockHash {
+	return bh.cptr()
+}
+
 // BlockHashLike is an interface for types that can provide a block hash pointer.
 type BlockHashLike interface {
 	blockHashPtr() *C.btck_BlockHash
 }
 
+// ParseBlockHash creates a new BlockHash from its hex display form, as shown by
+// bitcoind and block explorers.
+//
+// Parameters:
+//   - s: 64 hex characters in display byte order
+//
+// Returns an error with code ErrorCodeDeserialization if s is not a valid hash.
+func ParseBlockHash(s string) (*BlockHash, error) {
+	hash, err := parseHash("parse block hash", s)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockHash(hash), nil
+}
+
 // NewBlockHash creates a new BlockHash from a 32-byte hash value.
 //
 // Parameters:
@@ -67,13 +89,13 @@ func NewBlockHash(hashBytes [32]byte) *BlockHash {
 // Bytes returns the 32-byte representation of the block hash.
 func (bh *blockHashApi) Bytes() [32]byte {
 	var output [32]C.uchar
-	C.btck_block_hash_to_bytes(bh.ptr, &output[0])
+	C.btck_block_hash_to_bytes(bh.cptr(), &output[0])
 	return *(*[32]byte)(unsafe.Pointer(&output[0]))
 }
 
 // Copy creates a copy of the block hash.
 func (bh *blockHashApi) Copy() *BlockHash {
-	return newBlockHash(bh.ptr, false)
+	return newBlockHash(bh.cptr(), false)
 }
 
 // Equals checks if two block hashes are equal.
@@ -83,5 +105,96 @@ func (bh *blockHashApi) Copy() *BlockHash {
 //
 // Returns true if the block hashes are equal.
 func (bh *blockHashApi) Equals(other BlockHashLike) bool {
-	return C.btck_block_hash_equals(bh.ptr, other.blockHashPtr()) != 0
+	return C.btck_block_hash_equals(bh.cptr(), other.blockHashPtr()) != 0
+}
+
+// Key returns the block hash as a comparable value, e.g. for use as a map key.
+func (bh *blockHashApi) Key() BlockHashKey {
+	return bh.Bytes()
+}
+
+// String returns the hex encoding of the block hash in display byte order, as shown
+// by bitcoind.
+func (bh *blockHashApi) String() string {
+	return bh.Key().String()
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (bh *blockHashApi) MarshalText() ([]byte, error) {
+	return bh.Key().MarshalText()
+}
+
+// MarshalJSON encodes the block hash as a JSON string in its String form.
+func (bh *blockHashApi) MarshalJSON() ([]byte, error) {
+	return bh.Key().MarshalJSON()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the block hash with
+// the one parsed from its String form.
+func (bh *BlockHash) UnmarshalText(text []byte) error {
+	hash, err := parseHash("parse block hash", string(text))
+	if err != nil {
+		return err
+	}
+	bh.replace(hash)
+	return nil
+}
+
+// UnmarshalJSON replaces the block hash with the one parsed from a JSON string in
+// its String form.
+func (bh *BlockHash) UnmarshalJSON(data []byte) error {
+	hash, err := unmarshalHashJSON("parse block hash", data)
+	if err != nil {
+		return err
+	}
+	bh.replace(hash)
+	return nil
+}
+
+// replace destroys the current block hash, if any, and points bh to a new one.
+func (bh *BlockHash) replace(hash [32]byte) {
+	if bh.handle != nil {
+		bh.Destroy()
+	}
+	*bh = *NewBlockHash(hash)
+}
+
+// BlockHashKey is a block hash as a plain value in the internal byte order used by
+// Bytes. Unlike BlockHash it does not reference kernel memory, so it is comparable,
+// can be used as a map key and needs no Destroy.
+type BlockHashKey [32]byte
+
+// String returns the hex encoding of the block hash in display byte order.
+func (k BlockHashKey) String() string {
+	return hashString(k)
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (k BlockHashKey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the String form.
+func (k *BlockHashKey) UnmarshalText(text []byte) error {
+	hash, err := parseHash("parse block hash", string(text))
+	if err != nil {
+		return err
+	}
+	*k = hash
+	return nil
+}
+
+// MarshalJSON encodes the block hash as a JSON string in its String form.
+func (k BlockHashKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// UnmarshalJSON parses a JSON string in the String form.
+func (k *BlockHashKey) UnmarshalJSON(data []byte) error {
+	hash, err := unmarshalHashJSON("parse block hash", data)
+	if err != nil {
+		return err
+	}
+	*k = hash
+	return nil
 }
@@ -1,6 +1,8 @@
 package kernel
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -38,3 +40,87 @@ func TestBlockHash(t *testing.T) {
 		t.Errorf("hash.Equals(differentHash) = true, want false")
 	}
 }
+
+func TestBlockHashString(t *testing.T) {
+	const genesisHashHex = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+
+	hash, err := ParseBlockHash(genesisHashHex)
+	if err != nil {
+		t.Fatalf("ParseBlockHash() error = %v", err)
+	}
+	defer hash.Destroy()
+
+	if hash.String() != genesisHashHex {
+		t.Errorf("String() = %s, want %s", hash.String(), genesisHashHex)
+	}
+	// Bytes are in internal byte order, the reverse of the display order
+	if got := hash.Bytes(); got[0] != 0x6f || got[31] != 0x00 {
+		t.Errorf("Bytes() = %x, want internal byte order", got)
+	}
+
+	for _, invalid := range []string{"", "00", genesisHashHex + "00", "zz" + genesisHashHex[2:]} {
+		if _, err := ParseBlockHash(invalid); !errors.Is(err, ErrDeserialization) {
+			t.Errorf("ParseBlockHash(%q) error = %v, want %v", invalid, err, ErrDeserialization)
+		}
+	}
+}
+
+func TestBlockHashJSON(t *testing.T) {
+	const hashHex = "0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206"
+
+	hash, err := ParseBlockHash(hashHex)
+	if err != nil {
+		t.Fatalf("ParseBlockHash() error = %v", err)
+	}
+	defer hash.Destroy()
+
+	data, err := json.Marshal(struct {
+		Hash *BlockHash
+		Key  BlockHashKey
+	}{hash, hash.Key()})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"Hash":"` + hashHex + `","Key":"` + hashHex + `"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded struct {
+		Hash *BlockHash
+		Key  BlockHashKey
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	defer decoded.Hash.Destroy()
+	if !decoded.Hash.Equals(hash) || decoded.Key != hash.Key() {
+		t.Errorf("json.Unmarshal() = %s, %s, want %s", decoded.Hash, decoded.Key, hashHex)
+	}
+
+	if err := json.Unmarshal([]byte(`"abc"`), &decoded.Key); !errors.Is(err, ErrDeserialization) {
+		t.Errorf("json.Unmarshal() of invalid hash error = %v, want %v", err, ErrDeserialization)
+	}
+}
+
+func TestBlockHashKey(t *testing.T) {
+	first := NewBlockHash([32]byte{1})
+	defer first.Destroy()
+	second := first.Copy()
+	defer second.Destroy()
+
+	seen := map[BlockHashKey]int{first.Key(): 1}
+	seen[second.Key()]++
+	if len(seen) != 1 || seen[first.Key()] != 2 {
+		t.Errorf("Expected equal hashes to share a map key, got %v", seen)
+	}
+
+	text, err := first.Key().MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+	var key BlockHashKey
+	if err := key.UnmarshalText(text); err != nil || key != first.Key() {
+		t.Errorf("UnmarshalText(%s) = %s, %v, want %s", text, key, err, first.Key())
+	}
+}
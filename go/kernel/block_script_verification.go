@@ -0,0 +1,140 @@
+package kernel
+
+/*
+#include "kernel/bitcoinkernel.h"
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// InputScriptError describes an input of a block whose script failed verification in
+// VerifyBlockScripts.
+type InputScriptError struct {
+	Tx    uint64 // Index of the transaction in the block
+	Input uint64 // Index of the input in the transaction
+	Err   error  // Cause of the failure, e.g. ErrVerifyScriptVerifyInvalid
+}
+
+func (e *InputScriptError) Error() string {
+	return fmt.Sprintf("Input %d of transaction %d: %v", e.Input, e.Tx, e.Err)
+}
+
+func (e *InputScriptError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InputScriptError) isKernelError() {}
+
+// transactionScripts is a transaction of a block paired with the coins its inputs spend.
+type transactionScripts struct {
+	index        uint64
+	txTo         *C.btck_Transaction
+	spentOutputs *C.btck_TransactionSpentOutputs
+	inputs       uint64
+}
+
+// VerifyBlockScripts verifies the scripts of all inputs of a block against the coins
+// they spend, as recorded in the undo data of the block. The transactions are verified
+// in parallel, and each input like ScriptPubkey.Verify would. The data shared by the
+// signature hashes of a transaction's inputs is only computed once per transaction.
+//
+// The block is not validated otherwise, e.g. its transactions and amounts are not
+// checked. Use ChainstateManager.GetScriptFlagsForBlock to obtain the flags consensus
+// enforces for a block.
+//
+// Parameters:
+//   - block: Block whose inputs to verify
+//   - spentOutputs: Spent outputs of the block, e.g. read with ChainstateManager.ReadBlockSpentOutputs
+//   - flags: ScriptFlags controlling validation constraints
+//   - workers: Number of goroutines verifying transactions (0 for runtime.GOMAXPROCS)
+//
+// Returns the inputs that failed verification, ordered by transaction and input index,
+// or an error with code ErrorCodeInvalidArgument if the flags are invalid or the spent
+// outputs do not match the transactions of the block.
+func VerifyBlockScripts(block *Block, spentOutputs *BlockSpentOutputs, flags ScriptFlags, workers int) ([]*InputScriptError, error) {
+	const op = "verify block scripts"
+	if (flags & ^ScriptFlags(ScriptFlagsVerifyAll)) != 0 {
+		return nil, &Error{Op: op, Code: ErrorCodeInvalidArgument, Err: ErrVerifyScriptVerifyInvalidFlags}
+	}
+	if C.btck_script_verification_flags_is_valid_combination(C.btck_ScriptVerificationFlags(flags)) != 1 {
+		return nil, &Error{Op: op, Code: ErrorCodeInvalidArgument, Err: ErrVerifyScriptVerifyInvalidFlagsCombination}
+	}
+
+	txs, err := pairBlockTransactions(block, spentOutputs)
+	if err != nil {
+		return nil, &Error{Op: op, Code: ErrorCodeInvalidArgument, Err: err}
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(txs))
+
+	results := make([][]C.int, len(txs))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := next.Add(1) - 1
+				if i >= int64(len(txs)) {
+					return
+				}
+				tx := &txs[i]
+				inputResults := make([]C.int, tx.inputs)
+				if C.btck_transaction_verify_scripts(tx.txTo, tx.spentOutputs, C.btck_ScriptVerificationFlags(flags),
+					&inputResults[0], C.size_t(len(inputResults)), nil) != 1 {
+					results[i] = inputResults
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	// The paired pointers point into memory owned by the block and the spent outputs
+	runtime.KeepAlive(block)
+	runtime.KeepAlive(spentOutputs)
+
+	var failures []*InputScriptError
+	for i, inputResults := range results {
+		for input, valid := range inputResults {
+			if valid != 1 {
+				failures = append(failures, &InputScriptError{Tx: txs[i].index, Input: uint64(input), Err: ErrVerifyScriptVerifyInvalid})
+			}
+		}
+	}
+	return failures, nil
+}
+
+// pairBlockTransactions pairs each non-coinbase transaction of block with the coins its
+// inputs spend.
+func pairBlockTransactions(block *Block, spentOutputs *BlockSpentOutputs) ([]transactionScripts, error) {
+	cBlock := (*C.btck_Block)(block.ptr)
+	cSpentOutputs := (*C.btck_BlockSpentOutputs)(spentOutputs.ptr)
+
+	txCount := block.CountTransactions()
+	if txCount == 0 || spentOutputs.Count() != txCount-1 {
+		return nil, fmt.Errorf("block has %d transactions, but spent outputs of %d", txCount, spentOutputs.Count())
+	}
+
+	txs := make([]transactionScripts, 0, txCount-1)
+	for tx := uint64(1); tx < txCount; tx++ {
+		txTo := C.btck_block_get_transaction_at(cBlock, C.size_t(tx))
+		txSpentOutputs := C.btck_block_spent_outputs_get_transaction_spent_outputs_at(cSpentOutputs, C.size_t(tx-1))
+
+		inputCount := uint64(C.btck_transaction_count_inputs(txTo))
+		if coinCount := uint64(C.btck_transaction_spent_outputs_count(txSpentOutputs)); coinCount != inputCount {
+			return nil, fmt.Errorf("transaction %d has %d inputs, but spent outputs of %d", tx, inputCount, coinCount)
+		}
+		if inputCount == 0 {
+			continue
+		}
+		txs = append(txs, transactionScripts{index: tx, txTo: txTo, spentOutputs: txSpentOutputs, inputs: inputCount})
+	}
+	return txs, nil
+}
@@ -0,0 +1,92 @@
+package kernel
+
+import (
+	"errors"
+	"testing"
+)
+
+func (s *ChainstateManagerTestSuite) TestVerifyBlockScripts(t *testing.T) {
+	chain := s.Manager.GetActiveChain()
+	entry := chain.GetByHeight(202)
+
+	block, err := s.Manager.ReadBlock(entry)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	spentOutputs, err := s.Manager.ReadBlockSpentOutputs(entry)
+	if err != nil {
+		t.Fatalf("ReadBlockSpentOutputs() error = %v", err)
+	}
+	defer spentOutputs.Destroy()
+
+	flags := s.Manager.GetScriptFlagsForBlock(entry)
+	for _, workers := range []int{0, 1, 4} {
+		failures, err := VerifyBlockScripts(block, spentOutputs, flags, workers)
+		if err != nil {
+			t.Fatalf("VerifyBlockScripts() with %d workers error = %v", workers, err)
+		}
+		for _, failure := range failures {
+			t.Errorf("VerifyBlockScripts() with %d workers: %v", workers, failure)
+		}
+	}
+
+	// Corrupt the signatures of two inputs, which the block is not checked against
+	decoded, err := block.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := []InputScriptError{{Tx: 3, Input: 0}, {Tx: 17, Input: 0}}
+	for _, in := range want {
+		decoded.Transactions[in.Tx].Inputs[in.Input].Witness[0][10] ^= 0xff
+	}
+	tampered, err := NewBlock(decoded.Bytes())
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	defer tampered.Destroy()
+	for _, workers := range []int{0, 1, 4} {
+		failures, err := VerifyBlockScripts(tampered, spentOutputs, flags, workers)
+		if err != nil {
+			t.Fatalf("VerifyBlockScripts() of tampered block with %d workers error = %v", workers, err)
+		}
+		if len(failures) != len(want) {
+			t.Fatalf("VerifyBlockScripts() of tampered block with %d workers = %v, want %d failures", workers, failures, len(want))
+		}
+		for i, failure := range failures {
+			if failure.Tx != want[i].Tx || failure.Input != want[i].Input || !errors.Is(failure, ErrVerifyScriptVerifyInvalid) {
+				t.Errorf("VerifyBlockScripts() of tampered block with %d workers failure %d = %v, want input %d of transaction %d",
+					workers, i, failure, want[i].Input, want[i].Tx)
+			}
+		}
+	}
+
+	if _, err := VerifyBlockScripts(block, spentOutputs, ScriptFlags(1<<30), 1); !errors.Is(err, ErrVerifyScriptVerifyInvalidFlags) {
+		t.Errorf("VerifyBlockScripts() with invalid flags error = %v, want ErrVerifyScriptVerifyInvalidFlags", err)
+	}
+	_, err = VerifyBlockScripts(block, spentOutputs, ScriptFlagsVerifyWitness, 1)
+	if !errors.Is(err, ErrInvalidArgument) || !errors.Is(err, ErrVerifyScriptVerifyInvalidFlagsCombination) {
+		t.Errorf("VerifyBlockScripts() with invalid flag combination error = %v, want ErrVerifyScriptVerifyInvalidFlagsCombination", err)
+	}
+
+	// The undo data of another block does not match the transactions of the block
+	otherSpentOutputs, err := s.Manager.ReadBlockSpentOutputs(chain.GetByHeight(1))
+	if err != nil {
+		t.Fatalf("ReadBlockSpentOutputs() error = %v", err)
+	}
+	defer otherSpentOutputs.Destroy()
+	if _, err := VerifyBlockScripts(block, otherSpentOutputs, flags, 1); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("VerifyBlockScripts() with mismatched spent outputs error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestInputScriptError(t *testing.T) {
+	err := error(&InputScriptError{Tx: 3, Input: 1, Err: ErrVerifyScriptVerifyInvalid})
+	if !errors.Is(err, ErrVerifyScriptVerifyInvalid) {
+		t.Errorf("errors.Is(%v, ErrVerifyScriptVerifyInvalid) = false", err)
+	}
+	if got, want := err.Error(), "Input 1 of transaction 3: Script verification failed: Script verification failed"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
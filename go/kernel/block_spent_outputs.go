@@ -5,6 +5,8 @@ package kernel
 */
 import "C"
 import (
+	"io"
+	"iter"
 	"unsafe"
 )
 
@@ -35,6 +37,24 @@ func newBlockSpentOutputs(ptr *C.btck_BlockSpentOutputs, fromOwned bool) *BlockS
 	return &BlockSpentOutputs{handle: h}
 }
 
+// NewBlockSpentOutputs creates new block spent outputs from their raw serialized
+// undo data, in the format stored in the rev*.dat files.
+//
+// Parameters:
+//   - rawBlockSpentOutputs: Serialized block undo data as returned by Bytes
+//
+// Returns an error if the data is malformed or cannot be parsed.
+func NewBlockSpentOutputs(rawBlockSpentOutputs []byte) (*BlockSpentOutputs, error) {
+	if len(rawBlockSpentOutputs) == 0 {
+		return nil, &Error{Op: "create block spent outputs", Code: ErrorCodeDeserialization}
+	}
+	ptr := C.btck_block_spent_outputs_create(unsafe.Pointer(&rawBlockSpentOutputs[0]), C.size_t(len(rawBlockSpentOutputs)))
+	if ptr == nil {
+		return nil, &Error{Op: "create block spent outputs", Code: ErrorCodeDeserialization}
+	}
+	return newBlockSpentOutputs(ptr, true), nil
+}
+
 // Count returns the number of transaction spent outputs contained in this block's spent outputs.
 func (bso *BlockSpentOutputs) Count() uint64 {
 	return uint64(C.btck_block_spent_outputs_count((*C.btck_BlockSpentOutputs)(bso.ptr)))
@@ -48,13 +68,58 @@ func (bso *BlockSpentOutputs) Count() uint64 {
 // Parameters:
 //   - index: Index of the transaction spent outputs to retrieve
 //
-// Returns an error if the index is out of bounds.
+// Returns an error with code ErrorCodeInvalidArgument if the index is out of bounds.
 func (bso *BlockSpentOutputs) GetTransactionSpentOutputsAt(index uint64) (*TransactionSpentOutputsView, error) {
 	if index >= bso.Count() {
-		return nil, ErrKernelIndexOutOfBounds
+		return nil, &Error{Op: "get transaction spent outputs", Code: ErrorCodeInvalidArgument, Err: ErrKernelIndexOutOfBounds}
 	}
 	ptr := C.btck_block_spent_outputs_get_transaction_spent_outputs_at((*C.btck_BlockSpentOutputs)(bso.ptr), C.size_t(index))
-	return newTransactionSpentOutputsView(check(ptr)), nil
+	return newTransactionSpentOutputsView(check(ptr), bso.handle), nil
+}
+
+// Transactions returns an iterator over the spent outputs of each transaction in the
+// block, yielding the index of the transaction spent outputs together with them. As the
+// coinbase transaction spends no outputs, index i corresponds to transaction i+1 of the block.
+//
+// The yielded TransactionSpentOutputsViews are non-owned pointers that depend on the
+// lifetime of this BlockSpentOutputs.
+func (bso *BlockSpentOutputs) Transactions() iter.Seq2[uint64, *TransactionSpentOutputsView] {
+	return func(yield func(uint64, *TransactionSpentOutputsView) bool) {
+		count := bso.Count()
+		for i := uint64(0); i < count; i++ {
+			ptr := C.btck_block_spent_outputs_get_transaction_spent_outputs_at((*C.btck_BlockSpentOutputs)(bso.ptr), C.size_t(i))
+			if !yield(i, newTransactionSpentOutputsView(check(ptr), bso.handle)) {
+				return
+			}
+		}
+	}
+}
+
+// Bytes returns the undo data serialization of the block spent outputs, as stored
+// in the rev*.dat files (without the surrounding network magic, length and checksum).
+//
+// Returns an error if the serialization fails.
+func (bso *BlockSpentOutputs) Bytes() ([]byte, error) {
+	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_block_spent_outputs_to_bytes((*C.btck_BlockSpentOutputs)(bso.ptr), writer, userData)
+	})
+	if !ok {
+		return nil, &Error{Op: "serialize block spent outputs", Code: ErrorCodeSerialization}
+	}
+	return bytes, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same data as Bytes.
+func (bso *BlockSpentOutputs) MarshalBinary() ([]byte, error) {
+	return bso.Bytes()
+}
+
+// WriteTo implements io.WriterTo, streaming the serialized undo data to w without
+// buffering it.
+func (bso *BlockSpentOutputs) WriteTo(w io.Writer) (int64, error) {
+	return writeToWriter(w, "serialize block spent outputs", func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_block_spent_outputs_to_bytes((*C.btck_BlockSpentOutputs)(bso.ptr), writer, userData)
+	})
 }
 
 // Copy creates a shallow copy of the block spent outputs by incrementing its reference count.
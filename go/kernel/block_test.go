@@ -1,16 +1,26 @@
 package kernel
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
+	"io"
+	"runtime"
 	"testing"
 )
 
 func TestInvalidBlockData(t *testing.T) {
 	_, err := NewBlock([]byte{0x00, 0x01, 0x02})
-	var internalErr *InternalError
-	if !errors.As(err, &internalErr) {
-		t.Errorf("Expected InternalError, got %v", err)
+	var kernelErr *Error
+	if !errors.As(err, &kernelErr) || kernelErr.Op != "create block" {
+		t.Errorf("Expected Error for create block, got %v", err)
+	}
+	if !errors.Is(err, ErrDeserialization) {
+		t.Errorf("Expected %v, got %v", ErrDeserialization, err)
+	}
+
+	if _, err := NewBlock(nil); !errors.Is(err, ErrDeserialization) {
+		t.Errorf("NewBlock(nil) error = %v, want %v", err, ErrDeserialization)
 	}
 }
 
@@ -128,6 +138,95 @@ func TestBlockGetTransactionAt(t *testing.T) {
 	}
 }
 
+func TestBlockTransactions(t *testing.T) {
+	genesisHex := "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c0101000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+	genesisBytes, err := hex.DecodeString(genesisHex)
+	if err != nil {
+		t.Fatalf("Failed to decode genesis hex: %v", err)
+	}
+
+	block, err := NewBlock(genesisBytes)
+	if err != nil {
+		t.Fatalf("NewBlockFromRaw() error = %v", err)
+	}
+	defer block.Destroy()
+
+	var count uint64
+	for i, tx := range block.Transactions() {
+		if i != count {
+			t.Errorf("Transactions() yielded index %d, want %d", i, count)
+		}
+		if tx.CountOutputs() != 1 {
+			t.Errorf("Expected 1 output in genesis coinbase, got %d", tx.CountOutputs())
+		}
+		count++
+	}
+	if count != block.CountTransactions() {
+		t.Errorf("Transactions() yielded %d transactions, want %d", count, block.CountTransactions())
+	}
+}
+
+func TestBlockViewsAfterDestroy(t *testing.T) {
+	genesisHex := "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c0101000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+	genesisBytes, err := hex.DecodeString(genesisHex)
+	if err != nil {
+		t.Fatalf("Failed to decode genesis hex: %v", err)
+	}
+
+	// A view keeps its owner reachable, so the block is not finalized while the view is in use
+	tx := func() *TransactionView {
+		block, err := NewBlock(genesisBytes)
+		if err != nil {
+			t.Fatalf("NewBlock() error = %v", err)
+		}
+		tx, err := block.GetTransactionAt(0)
+		if err != nil {
+			t.Fatalf("GetTransactionAt(0) error = %v", err)
+		}
+		return tx
+	}()
+	runtime.GC()
+	runtime.GC()
+	if tx.CountOutputs() != 1 {
+		t.Errorf("Expected 1 output, got %d", tx.CountOutputs())
+	}
+
+	// Views derived from views share the owner of the block
+	block, err := NewBlock(genesisBytes)
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	blockTx, err := block.GetTransactionAt(0)
+	if err != nil {
+		t.Fatalf("GetTransactionAt(0) error = %v", err)
+	}
+	output, err := blockTx.GetOutput(0)
+	if err != nil {
+		t.Fatalf("GetOutput(0) error = %v", err)
+	}
+	txid := blockTx.GetTxid()
+
+	block.Destroy()
+
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { blockTx.CountInputs() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { output.Amount() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { output.ScriptPubkey() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { txid.Bytes() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { blockTx.Copy() })
+}
+
+// assertPanicsWith fails the test unless fn panics with want
+func assertPanicsWith(t *testing.T, want error, fn func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		if r := recover(); r != want {
+			t.Errorf("Expected panic with %v, got %v", want, r)
+		}
+	}()
+	fn()
+}
+
 func reverseBytes(data []byte) []byte {
 	result := make([]byte, len(data))
 	for i, b := range data {
@@ -135,3 +234,95 @@ func reverseBytes(data []byte) []byte {
 	}
 	return result
 }
+
+func TestReadBlockFromStream(t *testing.T) {
+	genesisHex := "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c0101000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+	genesisBytes, err := hex.DecodeString(genesisHex)
+	if err != nil {
+		t.Fatalf("Failed to decode genesis hex: %v", err)
+	}
+
+	// Two consecutive blocks are read one at a time
+	stream := bytes.NewReader(append(append([]byte{}, genesisBytes...), genesisBytes...))
+	for i := 0; i < 2; i++ {
+		block, err := ReadBlock(stream)
+		if err != nil {
+			t.Fatalf("ReadBlock() #%d error = %v", i, err)
+		}
+		if block.CountTransactions() != 1 {
+			t.Errorf("ReadBlock() #%d has %d transactions, want 1", i, block.CountTransactions())
+		}
+		block.Destroy()
+	}
+	if _, err := ReadBlock(stream); err != io.EOF {
+		t.Errorf("ReadBlock() at end of stream error = %v, want %v", err, io.EOF)
+	}
+
+	_, err = ReadBlock(bytes.NewReader(genesisBytes[:len(genesisBytes)-1]))
+	if !errors.Is(err, ErrDeserialization) || errors.Is(err, io.EOF) {
+		t.Errorf("ReadBlock() of truncated block error = %v, want %v", err, ErrDeserialization)
+	}
+}
+
+func TestBlockWriteTo(t *testing.T) {
+	genesisHex := "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c0101000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+	genesisBytes, err := hex.DecodeString(genesisHex)
+	if err != nil {
+		t.Fatalf("Failed to decode genesis hex: %v", err)
+	}
+	block, err := NewBlock(genesisBytes)
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	var buf bytes.Buffer
+	n, err := block.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(len(genesisBytes)) || !bytes.Equal(buf.Bytes(), genesisBytes) {
+		t.Errorf("WriteTo() wrote %d bytes %x, want %x", n, buf.Bytes(), genesisBytes)
+	}
+
+	marshaled, err := block.MarshalBinary()
+	if err != nil || !bytes.Equal(marshaled, genesisBytes) {
+		t.Errorf("MarshalBinary() = %x, %v, want %x", marshaled, err, genesisBytes)
+	}
+
+	// A failing writer aborts the serialization
+	writeErr := errors.New("disk full")
+	_, err = block.WriteTo(&failingWriter{limit: 10, err: writeErr})
+	if !errors.Is(err, writeErr) || !errors.Is(err, ErrSerialization) {
+		t.Errorf("WriteTo() with failing writer error = %v, want %v", err, writeErr)
+	}
+
+	// A panicking writer does not unwind through the kernel, but still reaches the caller
+	assertPanicsWith(t, writeErr, func() {
+		block.WriteTo(&panickingWriter{err: writeErr})
+	})
+}
+
+// panickingWriter panics with err on the first write
+type panickingWriter struct {
+	err error
+}
+
+func (w *panickingWriter) Write(p []byte) (int, error) {
+	panic(w.err)
+}
+
+// failingWriter accepts up to limit bytes and fails all writes afterwards
+type failingWriter struct {
+	limit   int
+	written int
+	err     error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.limit {
+		return 0, w.err
+	}
+	w.written += len(p)
+	return len(p), nil
+}
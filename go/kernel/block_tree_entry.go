@@ -4,6 +4,9 @@ package kernel
 #include "kernel/bitcoinkernel.h"
 */
 import "C"
+import (
+	"iter"
+)
 
 // BlockTreeEntry represents a pointer to an element in the block index currently
 // in memory of the chainstate manager.
@@ -14,18 +17,37 @@ import "C"
 // a parent, thus forming a tree. Each entry corresponds to a single block and may
 // be used to retrieve its data and validation status.
 type BlockTreeEntry struct {
-	ptr *C.btck_BlockTreeEntry
+	ptr   *C.btck_BlockTreeEntry
+	owner viewOwner
+}
+
+func (bi *BlockTreeEntry) cptr() *C.btck_BlockTreeEntry {
+	checkOwner(bi.owner)
+	return bi.ptr
 }
 
 // Height returns the height of this block in the block tree.
 func (bi *BlockTreeEntry) Height() int32 {
-	return int32(C.btck_block_tree_entry_get_height(bi.ptr))
+	return int32(C.btck_block_tree_entry_get_height(bi.cptr()))
 }
 
 // Hash returns the block hash associated with this block tree entry.
 func (bi *BlockTreeEntry) Hash() *BlockHashView {
-	ptr := C.btck_block_tree_entry_get_block_hash(bi.ptr)
-	return newBlockHashView(check(ptr))
+	ptr := C.btck_block_tree_entry_get_block_hash(bi.cptr())
+	return newBlockHashView(check(ptr), bi.owner)
+}
+
+// HasBlockData reports whether the full block data of this entry is stored on disk.
+// It is not for entries only known from their header, or whose block data has been
+// pruned.
+func (bi *BlockTreeEntry) HasBlockData() bool {
+	return C.btck_block_tree_entry_has_block_data(bi.cptr()) != 0
+}
+
+// HasUndoData reports whether the undo data of this entry is stored on disk, which is
+// only written once the block has been connected.
+func (bi *BlockTreeEntry) HasUndoData() bool {
+	return C.btck_block_tree_entry_has_undo_data(bi.cptr()) != 0
 }
 
 // Previous returns the previous block tree entry in the chain.
@@ -33,10 +55,24 @@ func (bi *BlockTreeEntry) Hash() *BlockHashView {
 // Returns nil if this is the genesis block. The returned entry is a non-owned
 // pointer valid for the lifetime of the chainstate manager.
 func (bi *BlockTreeEntry) Previous() *BlockTreeEntry {
-	ptr := C.btck_block_tree_entry_get_previous(bi.ptr)
+	ptr := C.btck_block_tree_entry_get_previous(bi.cptr())
 	if ptr == nil {
 		return nil
 	}
-	prevIndex := &BlockTreeEntry{ptr: ptr}
+	prevIndex := &BlockTreeEntry{ptr: ptr, owner: bi.owner}
 	return prevIndex
 }
+
+// Ancestors returns an iterator over the ancestors of this block tree entry, starting
+// with its parent and walking back to the genesis block.
+//
+// The yielded entries are non-owned pointers valid for the lifetime of the chainstate manager.
+func (bi *BlockTreeEntry) Ancestors() iter.Seq[*BlockTreeEntry] {
+	return func(yield func(*BlockTreeEntry) bool) {
+		for entry := bi.Previous(); entry != nil; entry = entry.Previous() {
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
@@ -36,3 +36,46 @@ func TestBlockTreeEntryGetPrevious(t *testing.T) {
 		t.Error("Genesis block should not have a previous block")
 	}
 }
+
+func TestBlockTreeEntryAncestors(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 5,
+	}
+	suite.Setup(t)
+
+	tip := suite.Manager.GetActiveChain().GetTip()
+
+	wantHeight := tip.Height() - 1
+	for ancestor := range tip.Ancestors() {
+		if ancestor.Height() != wantHeight {
+			t.Errorf("Ancestors() yielded height %d, want %d", ancestor.Height(), wantHeight)
+		}
+		wantHeight--
+	}
+	if wantHeight != -1 {
+		t.Errorf("Ancestors() stopped before genesis, next expected height %d", wantHeight)
+	}
+
+	// The genesis block has no ancestors
+	for range suite.Manager.GetActiveChain().GetGenesis().Ancestors() {
+		t.Error("Genesis block should not have ancestors")
+	}
+}
+
+func TestBlockTreeEntryAfterManagerDestroy(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 2,
+	}
+	suite.Setup(t)
+
+	chain := suite.Manager.GetActiveChain()
+	tip := chain.GetTip()
+	hash := tip.Hash()
+
+	suite.Manager.Destroy()
+
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { tip.Height() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { tip.Previous() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { hash.Bytes() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { chain.GetHeight() })
+}
@@ -12,6 +12,16 @@ import "C"
 // validation interface callbacks.
 type BlockValidationState struct {
 	ptr *C.btck_BlockValidationState
+
+	// Results copied from the kernel for callbacks dispatched asynchronously, used if ptr is nil
+	mode   ValidationMode
+	result BlockValidationResult
+}
+
+// capture returns a copy of the state that remains valid after the callback providing
+// it has returned.
+func (bvs *BlockValidationState) capture() *BlockValidationState {
+	return &BlockValidationState{mode: bvs.ValidationMode(), result: bvs.ValidationResult()}
 }
 
 // ValidationMode returns whether the block is valid, invalid, or encountered an error.
@@ -21,6 +31,9 @@ type BlockValidationState struct {
 //   - ValidationStateInvalid: Block failed validation
 //   - ValidationStateError: Internal error during validation
 func (bvs *BlockValidationState) ValidationMode() ValidationMode {
+	if bvs.ptr == nil {
+		return bvs.mode
+	}
 	mode := C.btck_block_validation_state_get_validation_mode(bvs.ptr)
 	return ValidationMode(mode)
 }
@@ -30,6 +43,9 @@ func (bvs *BlockValidationState) ValidationMode() ValidationMode {
 // This provides detailed information about the specific validation failure, such as
 // consensus violations, invalid headers, or missing previous blocks.
 func (bvs *BlockValidationState) ValidationResult() BlockValidationResult {
+	if bvs.ptr == nil {
+		return bvs.result
+	}
 	result := C.btck_block_validation_state_get_block_validation_result(bvs.ptr)
 	return BlockValidationResult(result)
 }
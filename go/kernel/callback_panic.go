@@ -0,0 +1,65 @@
+package kernel
+
+import (
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// CallbackPanicHandler is called with a panic raised by a Go callback invoked by the
+// kernel, after the panic was recovered.
+type CallbackPanicHandler func(err *CallbackPanicError)
+
+var (
+	callbackPanicHandler atomic.Pointer[CallbackPanicHandler]
+	lastCallbackPanic    atomic.Pointer[CallbackPanicError]
+)
+
+// SetCallbackPanicHandler sets the handler for panics raised by notification callbacks,
+// validation interface callbacks and log callbacks.
+//
+// Letting such a panic unwind through the kernel's C++ frames is undefined behaviour, so
+// the bridges invoking the callbacks recover it and the kernel carries on as if the
+// callback had returned. The recovered panic is recorded, see LastCallbackPanic, and
+// passed to the handler. The default handler logs it with the standard logger.
+//
+// Parameters:
+//   - handler: Called on the kernel thread that invoked the callback (can be nil to restore the default)
+func SetCallbackPanicHandler(handler CallbackPanicHandler) {
+	if handler == nil {
+		callbackPanicHandler.Store(nil)
+		return
+	}
+	callbackPanicHandler.Store(&handler)
+}
+
+// LastCallbackPanic returns the most recent panic recovered from a kernel callback, or
+// nil if no callback has panicked since the process started or ClearCallbackPanic was
+// called. Applications can poll it to shut down cleanly after a callback failed.
+func LastCallbackPanic() *CallbackPanicError {
+	return lastCallbackPanic.Load()
+}
+
+// ClearCallbackPanic forgets the panic returned by LastCallbackPanic.
+func ClearCallbackPanic() {
+	lastCallbackPanic.Store(nil)
+}
+
+// recoverCallbackPanic must be deferred directly by the bridges invoking Go callbacks.
+func recoverCallbackPanic(callback string) {
+	if r := recover(); r != nil {
+		handleCallbackPanic(&CallbackPanicError{Callback: callback, Value: r, Stack: debug.Stack()})
+	}
+}
+
+func handleCallbackPanic(err *CallbackPanicError) {
+	lastCallbackPanic.Store(err)
+
+	// A panicking handler must not unwind into the kernel either
+	defer func() { _ = recover() }()
+	if handler := callbackPanicHandler.Load(); handler != nil {
+		(*handler)(err)
+		return
+	}
+	log.Printf("kernel: %v\n%s", err, err.Stack)
+}
@@ -0,0 +1,98 @@
+package kernel
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCallbackPanicRecovered(t *testing.T) {
+	var mu sync.Mutex
+	var handled []*CallbackPanicError
+	SetCallbackPanicHandler(func(err *CallbackPanicError) {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, err)
+	})
+	t.Cleanup(func() {
+		SetCallbackPanicHandler(nil)
+		ClearCallbackPanic()
+	})
+	ClearCallbackPanic()
+
+	tipErr := errors.New("tip callback failure")
+	connected := 0
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 3,
+		NotificationCallbacks: &NotificationCallbacks{
+			OnBlockTip: func(state SynchronizationState, entry *BlockTreeEntry, progress float64) {
+				panic(tipErr)
+			},
+		},
+		ValidationCallbacks: &ValidationInterfaceCallbacks{
+			OnBlockConnected: func(block *Block, entry *BlockTreeEntry) {
+				defer block.Destroy()
+				connected++
+				panic("connected callback failure")
+			},
+		},
+	}
+	suite.Setup(t)
+
+	// Validation carried on past the panicking callbacks
+	if suite.ImportedBlocksCount != 3 || connected != 3 {
+		t.Fatalf("Imported %d blocks and connected %d, want 3", suite.ImportedBlocksCount, connected)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var tips, connects int
+	for _, err := range handled {
+		switch err.Callback {
+		case "OnBlockTip":
+			tips++
+			if err.Value != tipErr {
+				t.Errorf("Expected panic value %v, got %v", tipErr, err.Value)
+			}
+		case "OnBlockConnected":
+			connects++
+		}
+		if len(err.Stack) == 0 {
+			t.Errorf("Expected stack trace for %s panic", err.Callback)
+		}
+	}
+	if tips != 3 || connects != 3 {
+		t.Errorf("Handled %d OnBlockTip and %d OnBlockConnected panics, want 3 each", tips, connects)
+	}
+
+	last := LastCallbackPanic()
+	if last == nil {
+		t.Fatal("Expected LastCallbackPanic() to return the last panic")
+	}
+	if last != handled[len(handled)-1] {
+		t.Errorf("LastCallbackPanic() = %v, want %v", last, handled[len(handled)-1])
+	}
+	ClearCallbackPanic()
+	if LastCallbackPanic() != nil {
+		t.Error("Expected no panic after ClearCallbackPanic()")
+	}
+}
+
+func TestCallbackPanicHandlerPanics(t *testing.T) {
+	SetCallbackPanicHandler(func(err *CallbackPanicError) {
+		panic("handler failure")
+	})
+	t.Cleanup(func() {
+		SetCallbackPanicHandler(nil)
+		ClearCallbackPanic()
+	})
+
+	err := &CallbackPanicError{Callback: "OnProgress", Value: "callback failure"}
+	handleCallbackPanic(err)
+	if LastCallbackPanic() != err {
+		t.Errorf("LastCallbackPanic() = %v, want %v", LastCallbackPanic(), err)
+	}
+	if got := err.Error(); got != "Callback OnProgress panicked: callback failure" {
+		t.Errorf("Error() = %q", got)
+	}
+}
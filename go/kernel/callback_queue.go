@@ -0,0 +1,109 @@
+package kernel
+
+import (
+	"sync"
+)
+
+// callbackQueue runs notification and validation interface callbacks of the contexts
+// created from a ContextOptions, either inline on the kernel thread issuing them or,
+// once asynchronous dispatch is enabled, in order on a worker goroutine.
+//
+// The worker is started when a callback is queued and exits once the queue is empty,
+// so an idle queue does not hold on to a goroutine.
+type callbackQueue struct {
+	async    bool
+	capacity int // maximum number of pending callbacks, 0 if unbounded
+
+	mu      sync.Mutex
+	cond    sync.Cond // broadcast when the worker exits
+	pending []queuedCallback
+	running bool
+	dropped uint64 // callbacks discarded because the queue was full
+}
+
+type queuedCallback struct {
+	name string // callback name reported in CallbackPanicError
+	fn   func()
+}
+
+func newCallbackQueue() *callbackQueue {
+	q := &callbackQueue{}
+	q.cond.L = &q.mu
+	return q
+}
+
+// dispatch runs fn inline, or queues it for the worker if asynchronous dispatch is
+// enabled. fn must only capture data that remains valid after the kernel callback
+// returned.
+//
+// The kernel may issue callbacks while holding its chainstate lock, which queued
+// callbacks can acquire in turn, e.g. through ChainstateManager.GetActiveChain. So
+// dispatch never waits for the worker: if the queue is full, fn is dropped instead.
+//
+// Returns false if fn was dropped, in which case the caller must release the resources
+// fn captured, e.g. owned blocks.
+func (q *callbackQueue) dispatch(name string, fn func()) bool {
+	if !q.async {
+		fn()
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.capacity > 0 && len(q.pending) >= q.capacity {
+		q.dropped++
+		return false
+	}
+	q.pending = append(q.pending, queuedCallback{name: name, fn: fn})
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+	return true
+}
+
+func (q *callbackQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.cond.Broadcast()
+			q.mu.Unlock()
+			return
+		}
+		next := q.pending[0]
+		q.pending[0] = queuedCallback{}
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		next.invoke()
+	}
+}
+
+func (c queuedCallback) invoke() {
+	defer recoverCallbackPanic(c.name)
+	c.fn()
+}
+
+// drain blocks until all queued callbacks have returned.
+func (q *callbackQueue) drain() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.running {
+		q.cond.Wait()
+	}
+}
+
+// droppedCount returns the number of callbacks dropped because the queue was full.
+func (q *callbackQueue) droppedCount() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// len returns the number of callbacks waiting to be run.
+func (q *callbackQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
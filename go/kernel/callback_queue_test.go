@@ -0,0 +1,112 @@
+package kernel
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCallbackQueueSync(t *testing.T) {
+	q := newCallbackQueue()
+	ran := false
+	q.dispatch("OnProgress", func() { ran = true })
+	if !ran {
+		t.Error("Synchronous dispatch did not run the callback inline")
+	}
+	q.drain()
+}
+
+func TestCallbackQueueAsyncOrder(t *testing.T) {
+	q := newCallbackQueue()
+	q.async = true
+
+	release := make(chan struct{})
+	var order []int
+	q.dispatch("OnProgress", func() { <-release })
+	for i := 0; i < 100; i++ {
+		q.dispatch("OnProgress", func() { order = append(order, i) })
+	}
+	if got := q.len(); got == 0 {
+		t.Error("Expected queued callbacks while the worker is blocked")
+	}
+
+	close(release)
+	q.drain()
+	if len(order) != 100 {
+		t.Fatalf("Ran %d callbacks, want 100", len(order))
+	}
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("Callback %d ran at position %d", v, i)
+		}
+	}
+	if q.len() != 0 {
+		t.Errorf("len() = %d after drain, want 0", q.len())
+	}
+}
+
+func TestCallbackQueueCapacity(t *testing.T) {
+	q := newCallbackQueue()
+	q.async = true
+	q.capacity = 1
+
+	release := make(chan struct{})
+	q.dispatch("OnProgress", func() { <-release }) // picked up by the worker
+	for q.len() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	var ran atomic.Int32
+	q.dispatch("OnProgress", func() { ran.Add(1) }) // fills the queue
+
+	// A full queue must not block the kernel thread
+	done := make(chan struct{})
+	go func() {
+		if q.dispatch("OnProgress", func() { ran.Add(1) }) {
+			t.Error("dispatch() = true for a dropped callback")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatch() blocked on a full queue")
+	}
+	if got := q.droppedCount(); got != 1 {
+		t.Errorf("droppedCount() = %d, want 1", got)
+	}
+
+	close(release)
+	q.drain()
+	if got := ran.Load(); got != 1 {
+		t.Errorf("Ran %d queued callbacks, want 1", got)
+	}
+
+	// Once the queue has room again, callbacks are queued
+	q.dispatch("OnProgress", func() { ran.Add(1) })
+	q.drain()
+	if got := ran.Load(); got != 2 {
+		t.Errorf("Ran %d queued callbacks, want 2", got)
+	}
+}
+
+func TestCallbackQueuePanic(t *testing.T) {
+	SetCallbackPanicHandler(func(*CallbackPanicError) {})
+	t.Cleanup(func() {
+		SetCallbackPanicHandler(nil)
+		ClearCallbackPanic()
+	})
+
+	q := newCallbackQueue()
+	q.async = true
+	ran := false
+	q.dispatch("OnBlockConnected", func() { panic("callback failure") })
+	q.dispatch("OnBlockTip", func() { ran = true })
+	q.drain()
+
+	if !ran {
+		t.Error("A panicking callback stopped the queue")
+	}
+	if last := LastCallbackPanic(); last == nil || last.Callback != "OnBlockConnected" {
+		t.Errorf("LastCallbackPanic() = %v, want panic of OnBlockConnected", last)
+	}
+}
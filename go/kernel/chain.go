@@ -4,6 +4,9 @@ package kernel
 #include "kernel/bitcoinkernel.h"
 */
 import "C"
+import (
+	"iter"
+)
 
 // Chain represents the currently known best-chain associated with a chainstate.
 //
@@ -12,7 +15,13 @@ import "C"
 // retrieved from this chain is only consistent up to the point when new data
 // is processed in the chainstate manager.
 type Chain struct {
-	ptr *C.btck_Chain
+	ptr   *C.btck_Chain
+	owner viewOwner
+}
+
+func (c *Chain) cptr() *C.btck_Chain {
+	checkOwner(c.owner)
+	return c.ptr
 }
 
 // GetTip returns the block tree entry of the current chain tip.
@@ -20,22 +29,22 @@ type Chain struct {
 // Returns nil if the chain is empty. Once returned, there is no guarantee that it
 // remains in the active chain if new blocks are processed.
 func (c *Chain) GetTip() *BlockTreeEntry {
-	ptr := C.btck_chain_get_tip(c.ptr)
+	ptr := C.btck_chain_get_tip(c.cptr())
 	if ptr == nil {
 		return nil
 	}
-	return &BlockTreeEntry{ptr: ptr}
+	return &BlockTreeEntry{ptr: ptr, owner: c.owner}
 }
 
 // GetGenesis returns the block tree entry of the genesis block.
 //
 // Returns nil if the chain is empty.
 func (c *Chain) GetGenesis() *BlockTreeEntry {
-	ptr := C.btck_chain_get_genesis(c.ptr)
+	ptr := C.btck_chain_get_genesis(c.cptr())
 	if ptr == nil {
 		return nil
 	}
-	return &BlockTreeEntry{ptr: ptr}
+	return &BlockTreeEntry{ptr: ptr, owner: c.owner}
 }
 
 // GetByHeight retrieves a block tree entry by its height in the currently active chain.
@@ -46,23 +55,44 @@ func (c *Chain) GetGenesis() *BlockTreeEntry {
 // Parameters:
 //   - height: Block height to retrieve
 func (c *Chain) GetByHeight(height int32) *BlockTreeEntry {
-	ptr := C.btck_chain_get_by_height(c.ptr, C.int(height))
+	ptr := C.btck_chain_get_by_height(c.cptr(), C.int(height))
 	if ptr == nil {
 		return nil
 	}
-	return &BlockTreeEntry{ptr}
+	return &BlockTreeEntry{ptr: ptr, owner: c.owner}
 }
 
 // Contains checks whether the given block tree entry is part of this chain.
 //
 // Returns true if the block tree entry is in the currently active chain, false otherwise.
 func (c *Chain) Contains(blockTreeEntry *BlockTreeEntry) bool {
-	return C.btck_chain_contains(c.ptr, blockTreeEntry.ptr) != 0
+	return C.btck_chain_contains(c.cptr(), blockTreeEntry.cptr()) != 0
 }
 
 // GetHeight returns the height of the chain's tip.
 //
 // This is the height of the most recent block in the chain.
 func (c *Chain) GetHeight() int32 {
-	return int32(C.btck_chain_get_height(c.ptr))
+	return int32(C.btck_chain_get_height(c.cptr()))
+}
+
+// Entries returns an iterator over the block tree entries of the chain with heights
+// in the inclusive range [from, to], in ascending height order.
+//
+// The iteration stops early if the chain no longer contains a height in the range,
+// e.g. if to is beyond the current tip. Each entry is looked up at the time it is
+// yielded, so processing blocks while iterating may yield entries of the new chain.
+//
+// Parameters:
+//   - from: Height of the first entry to yield
+//   - to: Height of the last entry to yield
+func (c *Chain) Entries(from, to int32) iter.Seq[*BlockTreeEntry] {
+	return func(yield func(*BlockTreeEntry) bool) {
+		for height := max(from, 0); height <= to; height++ {
+			entry := c.GetByHeight(height)
+			if entry == nil || !yield(entry) {
+				return
+			}
+		}
+	}
 }
@@ -5,6 +5,8 @@ package kernel
 */
 import "C"
 import (
+	"fmt"
+	"strings"
 	"unsafe"
 )
 
@@ -39,7 +41,12 @@ func newChainParameters(ptr *C.btck_ChainParameters, fromOwned bool) *ChainParam
 //
 // Parameters:
 //   - chainType: One of ChainTypeMainnet, ChainTypeTestnet, ChainTypeTestnet4, ChainTypeSignet, or ChainTypeRegtest
+//
+// Returns an error with code ErrorCodeInvalidArgument for an unknown chain type.
 func NewChainParameters(chainType ChainType) (*ChainParameters, error) {
+	if !chainType.valid() {
+		return nil, &Error{Op: "create chain parameters", Code: ErrorCodeInvalidArgument, Err: fmt.Errorf("unknown chain type %d", uint8(chainType))}
+	}
 	ptr := C.btck_chain_parameters_create(chainType.c())
 	return newChainParameters(check(ptr), true), nil
 }
@@ -49,6 +56,50 @@ func (cp *ChainParameters) Copy() *ChainParameters {
 	return newChainParameters((*C.btck_ChainParameters)(cp.ptr), false)
 }
 
+// NetworkInfo describes the network of a chain: how its peers identify each other
+// and how its addresses and keys are encoded.
+type NetworkInfo struct {
+	MessageStart        [4]byte      // Magic bytes starting every P2P message
+	DefaultPort         uint16       // Default port of the P2P network
+	Bech32HRP           string       // Human readable part of bech32 addresses, e.g. "bc"
+	PubkeyAddressPrefix byte         // Base58 version byte of pay-to-pubkey-hash addresses
+	ScriptAddressPrefix byte         // Base58 version byte of pay-to-script-hash addresses
+	SecretKeyPrefix     byte         // Base58 version byte of WIF private keys
+	ExtPublicKeyPrefix  [4]byte      // Base58 version bytes of BIP32 extended public keys
+	ExtSecretKeyPrefix  [4]byte      // Base58 version bytes of BIP32 extended private keys
+	GenesisHash         BlockHashKey // Hash of the genesis block
+}
+
+// NetworkInfo returns the network metadata of the chain parameters.
+//
+// Returns an error if the kernel cannot report the metadata.
+func (cp *ChainParameters) NetworkInfo() (NetworkInfo, error) {
+	var info C.btck_NetworkInfo
+	if C.btck_chain_parameters_get_network_info((*C.btck_ChainParameters)(cp.ptr), &info) != 0 {
+		return NetworkInfo{}, &Error{Op: "get network info", Code: ErrorCodeInternal}
+	}
+	result := NetworkInfo{
+		DefaultPort:         uint16(info.default_port),
+		Bech32HRP:           C.GoStringN(&info.bech32_hrp[0], C.int(info.bech32_hrp_len)),
+		PubkeyAddressPrefix: byte(info.pubkey_address_prefix),
+		ScriptAddressPrefix: byte(info.script_address_prefix),
+		SecretKeyPrefix:     byte(info.secret_key_prefix),
+	}
+	for i := range result.MessageStart {
+		result.MessageStart[i] = byte(info.message_start[i])
+		result.ExtPublicKeyPrefix[i] = byte(info.ext_public_key_prefix[i])
+		result.ExtSecretKeyPrefix[i] = byte(info.ext_secret_key_prefix[i])
+	}
+	for i := range result.GenesisHash {
+		result.GenesisHash[i] = byte(info.genesis_block_hash[i])
+	}
+	return result, nil
+}
+
+// ChainType identifies one of the networks chain parameters can be created for.
+//
+// It implements encoding.TextMarshaler and encoding.TextUnmarshaler using the names
+// accepted by ParseChainType, so it can be read from configuration files directly.
 type ChainType C.btck_ChainType
 
 const (
@@ -59,6 +110,16 @@ const (
 	ChainTypeRegtest  ChainType = C.btck_ChainType_REGTEST
 )
 
+// ChainTypeInvalid is returned by ParseChainType for an unknown name. It does not
+// identify a network.
+const ChainTypeInvalid ChainType = 0xFF
+
+// valid reports whether t identifies a network.
+func (t ChainType) valid() bool {
+	_, ok := chainTypeNames[t]
+	return ok
+}
+
 func (t ChainType) c() C.btck_ChainType {
 	switch t {
 	case ChainTypeMainnet, ChainTypeTestnet, ChainTypeTestnet4, ChainTypeSignet, ChainTypeRegtest:
@@ -67,3 +128,83 @@ func (t ChainType) c() C.btck_ChainType {
 		panic("Invalid chain type")
 	}
 }
+
+// chainTypeNames holds the name String returns for each chain type, matching the
+// values of Bitcoin Core's -chain option.
+var chainTypeNames = map[ChainType]string{
+	ChainTypeMainnet:  "main",
+	ChainTypeTestnet:  "test",
+	ChainTypeTestnet4: "testnet4",
+	ChainTypeSignet:   "signet",
+	ChainTypeRegtest:  "regtest",
+}
+
+// chainTypeAliases holds names ParseChainType accepts in addition to chainTypeNames.
+var chainTypeAliases = map[string]ChainType{
+	"mainnet":  ChainTypeMainnet,
+	"testnet":  ChainTypeTestnet,
+	"testnet3": ChainTypeTestnet,
+}
+
+// ParseChainType returns the chain type with the given name. It accepts the values of
+// Bitcoin Core's -chain option ("main", "test", "testnet4", "signet" and "regtest") as
+// well as "mainnet", "testnet" and "testnet3", ignoring case and surrounding spaces.
+//
+// Returns ChainTypeInvalid and an error with code ErrorCodeInvalidArgument if the name
+// is unknown.
+func ParseChainType(name string) (ChainType, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	for chainType, chainName := range chainTypeNames {
+		if normalized == chainName {
+			return chainType, nil
+		}
+	}
+	if chainType, ok := chainTypeAliases[normalized]; ok {
+		return chainType, nil
+	}
+	return ChainTypeInvalid, &Error{Op: "parse chain type", Code: ErrorCodeInvalidArgument, Err: fmt.Errorf("unknown chain type %q", name)}
+}
+
+// String returns the name of the chain type as used by Bitcoin Core's -chain option,
+// e.g. "main" or "regtest".
+func (t ChainType) String() string {
+	if name, ok := chainTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ChainType(%d)", uint8(t))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// Returns an error with code ErrorCodeInvalidArgument for an unknown chain type.
+func (t ChainType) MarshalText() ([]byte, error) {
+	name, ok := chainTypeNames[t]
+	if !ok {
+		return nil, &Error{Op: "marshal chain type", Code: ErrorCodeInvalidArgument, Err: fmt.Errorf("unknown chain type %d", uint8(t))}
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, see ParseChainType.
+func (t *ChainType) UnmarshalText(text []byte) error {
+	chainType, err := ParseChainType(string(text))
+	if err != nil {
+		return err
+	}
+	*t = chainType
+	return nil
+}
+
+// NetworkInfo returns the network metadata of the chain type, see
+// ChainParameters.NetworkInfo.
+//
+// Returns an error with code ErrorCodeInvalidArgument for an unknown chain type, or an
+// error if the chain parameters cannot be created or do not report the metadata.
+func (t ChainType) NetworkInfo() (NetworkInfo, error) {
+	params, err := NewChainParameters(t)
+	if err != nil {
+		return NetworkInfo{}, err
+	}
+	defer params.Destroy()
+	return params.NetworkInfo()
+}
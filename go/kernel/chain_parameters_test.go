@@ -1,6 +1,7 @@
 package kernel
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 )
@@ -37,6 +38,12 @@ func TestNewChainParameters(t *testing.T) {
 			chainType: ChainTypeRegtest,
 			wantErr:   false,
 		},
+		{
+			name:      "Invalid chain type",
+			chainType: ChainTypeInvalid,
+			wantErr:   true,
+			errType:   ErrInvalidArgument,
+		},
 	}
 
 	for _, tt := range tests {
@@ -73,3 +80,118 @@ func TestNewChainParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestChainParametersNetworkInfo(t *testing.T) {
+	bitcoinPrefixes := [3]byte{0, 5, 128}
+	testPrefixes := [3]byte{111, 196, 239}
+	tests := []struct {
+		chainType    ChainType
+		messageStart [4]byte
+		port         uint16
+		hrp          string
+		prefixes     [3]byte
+		extPublic    [4]byte
+		genesis      string
+	}{
+		{ChainTypeMainnet, [4]byte{0xf9, 0xbe, 0xb4, 0xd9}, 8333, "bc", bitcoinPrefixes, [4]byte{0x04, 0x88, 0xb2, 0x1e},
+			"000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"},
+		{ChainTypeTestnet, [4]byte{0x0b, 0x11, 0x09, 0x07}, 18333, "tb", testPrefixes, [4]byte{0x04, 0x35, 0x87, 0xcf},
+			"000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943"},
+		{ChainTypeTestnet4, [4]byte{0x1c, 0x16, 0x3f, 0x28}, 48333, "tb", testPrefixes, [4]byte{0x04, 0x35, 0x87, 0xcf},
+			"00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043"},
+		{ChainTypeSignet, [4]byte{0x0a, 0x03, 0xcf, 0x40}, 38333, "tb", testPrefixes, [4]byte{0x04, 0x35, 0x87, 0xcf},
+			"00000008819873e925422c1ff0f99f7cc9bbb232af63a077a480a3633bee1ef6"},
+		{ChainTypeRegtest, [4]byte{0xfa, 0xbf, 0xb5, 0xda}, 18444, "bcrt", testPrefixes, [4]byte{0x04, 0x35, 0x87, 0xcf},
+			"0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chainType.String(), func(t *testing.T) {
+			info, err := tt.chainType.NetworkInfo()
+			if err != nil {
+				t.Fatalf("NetworkInfo() error = %v", err)
+			}
+			if info.MessageStart != tt.messageStart {
+				t.Errorf("MessageStart = %x, want %x", info.MessageStart, tt.messageStart)
+			}
+			if info.DefaultPort != tt.port {
+				t.Errorf("DefaultPort = %d, want %d", info.DefaultPort, tt.port)
+			}
+			if info.Bech32HRP != tt.hrp {
+				t.Errorf("Bech32HRP = %q, want %q", info.Bech32HRP, tt.hrp)
+			}
+			if got := [3]byte{info.PubkeyAddressPrefix, info.ScriptAddressPrefix, info.SecretKeyPrefix}; got != tt.prefixes {
+				t.Errorf("Base58 prefixes = %v, want %v", got, tt.prefixes)
+			}
+			if info.ExtPublicKeyPrefix != tt.extPublic {
+				t.Errorf("ExtPublicKeyPrefix = %x, want %x", info.ExtPublicKeyPrefix, tt.extPublic)
+			}
+			if info.GenesisHash.String() != tt.genesis {
+				t.Errorf("GenesisHash = %s, want %s", info.GenesisHash, tt.genesis)
+			}
+		})
+	}
+
+	if _, err := ChainTypeInvalid.NetworkInfo(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("NetworkInfo() of an unknown chain type error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestParseChainType(t *testing.T) {
+	tests := map[string]ChainType{
+		"main":       ChainTypeMainnet,
+		"mainnet":    ChainTypeMainnet,
+		"test":       ChainTypeTestnet,
+		"testnet":    ChainTypeTestnet,
+		"testnet3":   ChainTypeTestnet,
+		"testnet4":   ChainTypeTestnet4,
+		"signet":     ChainTypeSignet,
+		" RegTest\n": ChainTypeRegtest,
+	}
+	for name, want := range tests {
+		got, err := ParseChainType(name)
+		if err != nil || got != want {
+			t.Errorf("ParseChainType(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+
+	if got, err := ParseChainType("simnet"); got != ChainTypeInvalid || !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("ParseChainType(\"simnet\") = %v, %v, want %v, ErrInvalidArgument", got, err, ChainTypeInvalid)
+	}
+}
+
+func TestChainTypeString(t *testing.T) {
+	for _, chainType := range []ChainType{ChainTypeMainnet, ChainTypeTestnet, ChainTypeTestnet4, ChainTypeSignet, ChainTypeRegtest} {
+		parsed, err := ParseChainType(chainType.String())
+		if err != nil || parsed != chainType {
+			t.Errorf("ParseChainType(%q) = %v, %v, want %v", chainType.String(), parsed, err, chainType)
+		}
+	}
+	if got := ChainType(42).String(); got != "ChainType(42)" {
+		t.Errorf("String() = %q for an unknown chain type", got)
+	}
+}
+
+func TestChainTypeText(t *testing.T) {
+	var config struct {
+		Chain ChainType `json:"chain"`
+	}
+	if err := json.Unmarshal([]byte(`{"chain":"signet"}`), &config); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if config.Chain != ChainTypeSignet {
+		t.Errorf("Chain = %v, want signet", config.Chain)
+	}
+
+	encoded, err := json.Marshal(config)
+	if err != nil || string(encoded) != `{"chain":"signet"}` {
+		t.Errorf("Marshal() = %s, %v", encoded, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"chain":"simnet"}`), &config); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Unmarshal() of an unknown chain error = %v, want ErrInvalidArgument", err)
+	}
+	if _, err := ChainType(42).MarshalText(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("MarshalText() of an unknown chain error = %v, want ErrInvalidArgument", err)
+	}
+}
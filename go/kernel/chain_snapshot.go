@@ -0,0 +1,111 @@
+package kernel
+
+import (
+	"encoding/binary"
+	"iter"
+	"math/big"
+	"sync"
+)
+
+// ChainSnapshot is an immutable copy of the active chain taken by
+// ChainstateManager.SnapshotChain. Unlike Chain, it does not change when blocks
+// are processed, holds no kernel resources and is safe for concurrent use, e.g. by
+// HTTP handlers or indexers reading while another goroutine validates blocks.
+type ChainSnapshot struct {
+	hashes []BlockHashKey // indexed by height
+	work   *big.Int
+
+	indexOnce sync.Once
+	index     map[BlockHashKey]int32
+}
+
+// decodeChainSnapshot parses the serialization of
+// btck_chainstate_manager_snapshot_active_chain.
+func decodeChainSnapshot(raw []byte) (*ChainSnapshot, error) {
+	if len(raw) < 4+32 {
+		return nil, &SerializationError{"Chain snapshot too short"}
+	}
+	height := int32(binary.LittleEndian.Uint32(raw))
+	var work [32]byte
+	copy(work[:], raw[4:36])
+	raw = raw[36:]
+	if height < -1 || len(raw) != (int(height)+1)*32 {
+		return nil, &SerializationError{"Chain snapshot length does not match its height"}
+	}
+
+	hashes := make([]BlockHashKey, height+1)
+	for i := range hashes {
+		copy(hashes[i][:], raw[i*32:])
+	}
+	return &ChainSnapshot{hashes: hashes, work: new(big.Int).SetBytes(reverseHash(work))}, nil
+}
+
+// Height returns the height of the tip of the snapshot, or -1 if the chain was empty.
+func (s *ChainSnapshot) Height() int32 {
+	return int32(len(s.hashes)) - 1
+}
+
+// Tip returns the tip of the snapshot.
+//
+// Returns false if the chain was empty.
+func (s *ChainSnapshot) Tip() (BlockRef, bool) {
+	if len(s.hashes) == 0 {
+		return BlockRef{Height: -1}, false
+	}
+	height := s.Height()
+	return BlockRef{Hash: s.hashes[height], Height: height}, true
+}
+
+// Work returns the total proof of work of the chain up to and including the tip, as
+// the expected number of hashes needed to produce it.
+func (s *ChainSnapshot) Work() *big.Int {
+	return new(big.Int).Set(s.work)
+}
+
+// Hash returns the hash of the block at height.
+//
+// Returns false if height is outside the snapshot.
+func (s *ChainSnapshot) Hash(height int32) (BlockHashKey, bool) {
+	if height < 0 || height > s.Height() {
+		return BlockHashKey{}, false
+	}
+	return s.hashes[height], true
+}
+
+// HeightOf returns the height of the block with the given hash.
+//
+// Returns false if the block is not part of the snapshot. The first call builds an
+// index of the snapshot, so later lookups take constant time.
+func (s *ChainSnapshot) HeightOf(hash BlockHashKey) (int32, bool) {
+	s.indexOnce.Do(func() {
+		s.index = make(map[BlockHashKey]int32, len(s.hashes))
+		for height, h := range s.hashes {
+			s.index[h] = int32(height)
+		}
+	})
+	height, ok := s.index[hash]
+	return height, ok
+}
+
+// Contains reports whether the block with the given hash is part of the snapshot.
+func (s *ChainSnapshot) Contains(hash BlockHashKey) bool {
+	_, ok := s.HeightOf(hash)
+	return ok
+}
+
+// Range returns an iterator over the heights and hashes of the blocks with heights in
+// the inclusive range [from, to], in ascending height order. The range is clamped to
+// the snapshot.
+//
+// Parameters:
+//   - from: Height of the first block to yield
+//   - to: Height of the last block to yield
+func (s *ChainSnapshot) Range(from, to int32) iter.Seq2[int32, BlockHashKey] {
+	return func(yield func(int32, BlockHashKey) bool) {
+		for height := max(from, 0); height <= min(to, s.Height()); height++ {
+			if !yield(height, s.hashes[height]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package kernel
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func encodeChainSnapshot(height int32, work byte, hashes []BlockHashKey) []byte {
+	raw := binary.LittleEndian.AppendUint32(nil, uint32(height))
+	var workBytes [32]byte
+	workBytes[0] = work
+	raw = append(raw, workBytes[:]...)
+	for _, hash := range hashes {
+		raw = append(raw, hash[:]...)
+	}
+	return raw
+}
+
+func TestDecodeChainSnapshot(t *testing.T) {
+	hashes := []BlockHashKey{{0x01}, {0x02}, {0x03}}
+	snapshot, err := decodeChainSnapshot(encodeChainSnapshot(2, 6, hashes))
+	if err != nil {
+		t.Fatalf("decodeChainSnapshot() error = %v", err)
+	}
+
+	if snapshot.Height() != 2 {
+		t.Errorf("Height() = %d, want 2", snapshot.Height())
+	}
+	if tip, ok := snapshot.Tip(); !ok || tip != (BlockRef{Hash: hashes[2], Height: 2}) {
+		t.Errorf("Tip() = %v, %v", tip, ok)
+	}
+	if snapshot.Work().Cmp(big.NewInt(6)) != 0 {
+		t.Errorf("Work() = %v, want 6", snapshot.Work())
+	}
+	snapshot.Work().SetInt64(0)
+	if snapshot.Work().Sign() == 0 {
+		t.Error("Modifying the result of Work() changed the snapshot")
+	}
+
+	if hash, ok := snapshot.Hash(1); !ok || hash != hashes[1] {
+		t.Errorf("Hash(1) = %s, %v", hash, ok)
+	}
+	for _, height := range []int32{-1, 3} {
+		if _, ok := snapshot.Hash(height); ok {
+			t.Errorf("Hash(%d) found a block", height)
+		}
+	}
+	if height, ok := snapshot.HeightOf(hashes[2]); !ok || height != 2 {
+		t.Errorf("HeightOf() = %d, %v, want 2", height, ok)
+	}
+	if snapshot.Contains(BlockHashKey{0xff}) {
+		t.Error("Contains() found an unknown block")
+	}
+
+	var heights []int32
+	for height, hash := range snapshot.Range(-5, 10) {
+		if hash != hashes[height] {
+			t.Errorf("Range() yielded %s at height %d", hash, height)
+		}
+		heights = append(heights, height)
+	}
+	if len(heights) != 3 || heights[0] != 0 || heights[2] != 2 {
+		t.Errorf("Range() yielded heights %v, want [0 1 2]", heights)
+	}
+}
+
+func TestDecodeChainSnapshotEmpty(t *testing.T) {
+	snapshot, err := decodeChainSnapshot(encodeChainSnapshot(-1, 0, nil))
+	if err != nil {
+		t.Fatalf("decodeChainSnapshot() error = %v", err)
+	}
+	if snapshot.Height() != -1 {
+		t.Errorf("Height() = %d, want -1", snapshot.Height())
+	}
+	if _, ok := snapshot.Tip(); ok {
+		t.Error("Tip() found a block in an empty snapshot")
+	}
+	for range snapshot.Range(0, 10) {
+		t.Error("Range() yielded a block of an empty snapshot")
+	}
+}
+
+func TestDecodeChainSnapshotInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"truncated header": make([]byte, 10),
+		"missing hashes":   encodeChainSnapshot(2, 0, []BlockHashKey{{0x01}}),
+		"extra hashes":     encodeChainSnapshot(0, 0, []BlockHashKey{{0x01}, {0x02}}),
+		"negative height":  encodeChainSnapshot(-2, 0, nil),
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			var serErr *SerializationError
+			if _, err := decodeChainSnapshot(raw); !errors.As(err, &serErr) {
+				t.Errorf("decodeChainSnapshot() error = %v, want SerializationError", err)
+			}
+		})
+	}
+}
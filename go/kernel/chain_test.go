@@ -54,4 +54,26 @@ func TestChain(t *testing.T) {
 	if !containsBlock1 {
 		t.Error("Chain should contain block at height 1")
 	}
+
+	// Test Entries
+	var heights []int32
+	for entry := range chain.Entries(1, tipHeight+5) {
+		heights = append(heights, entry.Height())
+	}
+	if len(heights) != int(tipHeight) {
+		t.Fatalf("Entries(1, %d) yielded %d entries, want %d", tipHeight+5, len(heights), tipHeight)
+	}
+	for i, height := range heights {
+		if height != int32(i+1) {
+			t.Errorf("Entries() entry %d has height %d, want %d", i, height, i+1)
+		}
+	}
+
+	var count int
+	for range chain.Entries(0, 0) {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Entries(0, 0) yielded %d entries, want 1", count)
+	}
 }
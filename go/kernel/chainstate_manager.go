@@ -6,6 +6,8 @@ package kernel
 */
 import "C"
 import (
+	"context"
+	"sync"
 	"unsafe"
 )
 
@@ -19,11 +21,19 @@ func (chainstateManagerCFuncs) destroy(ptr unsafe.Pointer) {
 // retrieving data from the chain.
 type ChainstateManager struct {
 	*uniqueHandle
+	context *Context
 }
 
-func newChainstateManager(ptr *C.btck_ChainstateManager) *ChainstateManager {
+func newChainstateManager(ptr *C.btck_ChainstateManager, context *Context) *ChainstateManager {
 	h := newUniqueHandle(unsafe.Pointer(ptr), chainstateManagerCFuncs{})
-	return &ChainstateManager{uniqueHandle: h}
+	return &ChainstateManager{uniqueHandle: h, context: context}
+}
+
+// Destroy destroys the chainstate manager, which flushes the chainstate to disk, and
+// then releases its reference to the kernel context.
+func (cm *ChainstateManager) Destroy() {
+	cm.uniqueHandle.Destroy()
+	cm.context.Destroy()
 }
 
 // NewChainstateManager creates a new chainstate manager for validation and chain queries.
@@ -38,9 +48,9 @@ func newChainstateManager(ptr *C.btck_ChainstateManager) *ChainstateManager {
 func NewChainstateManager(options *ChainstateManagerOptions) (*ChainstateManager, error) {
 	ptr := C.btck_chainstate_manager_create((*C.btck_ChainstateManagerOptions)(options.ptr))
 	if ptr == nil {
-		return nil, &InternalError{"Failed to create chainstate manager"}
+		return nil, &Error{Op: "create chainstate manager", Code: ErrorCodeInternal}
 	}
-	return newChainstateManager(ptr), nil
+	return newChainstateManager(ptr, options.context.Copy()), nil
 }
 
 // ReadBlock reads the block from disk that the block tree entry points to.
@@ -48,11 +58,15 @@ func NewChainstateManager(options *ChainstateManagerOptions) (*ChainstateManager
 // Parameters:
 //   - blockTreeEntry: Block index entry obtained from GetBlockTreeEntryByHash or chain queries
 //
-// Returns an error if the block cannot be read from disk.
+// Returns an error with code ErrorCodeBlockNotFound or ErrorCodeBlockPruned if the block
+// data is not stored, or ErrorCodeInternal if it cannot be read from disk.
 func (cm *ChainstateManager) ReadBlock(blockTreeEntry *BlockTreeEntry) (*Block, error) {
-	ptr := C.btck_block_read((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr)
+	if err := cm.fatalError("read block"); err != nil {
+		return nil, err
+	}
+	ptr := C.btck_block_read((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.cptr())
 	if ptr == nil {
-		return nil, &InternalError{"Failed to read block"}
+		return nil, cm.readError("read block", blockTreeEntry, false)
 	}
 	return newBlock(ptr, true), nil
 }
@@ -62,15 +76,66 @@ func (cm *ChainstateManager) ReadBlock(blockTreeEntry *BlockTreeEntry) (*Block,
 // Parameters:
 //   - blockTreeEntry: Block index entry for the block whose spent outputs to read
 //
-// Returns an error if the undo data cannot be read from disk.
+// Returns an error with code ErrorCodeBlockNotFound or ErrorCodeBlockPruned if the block
+// data is not stored, ErrorCodeUndoMissing if the block has not been connected, or
+// ErrorCodeInternal if the undo data cannot be read from disk.
 func (cm *ChainstateManager) ReadBlockSpentOutputs(blockTreeEntry *BlockTreeEntry) (*BlockSpentOutputs, error) {
-	ptr := C.btck_block_spent_outputs_read((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr)
+	if err := cm.fatalError("read block spent outputs"); err != nil {
+		return nil, err
+	}
+	ptr := C.btck_block_spent_outputs_read((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.cptr())
 	if ptr == nil {
-		return nil, &InternalError{"Failed to read block spent outputs"}
+		return nil, cm.readError("read block spent outputs", blockTreeEntry, true)
 	}
 	return newBlockSpentOutputs(ptr, true), nil
 }
 
+// HavePruned reports whether any block files of the chainstate manager have ever been
+// pruned.
+func (cm *ChainstateManager) HavePruned() bool {
+	return C.btck_chainstate_manager_have_pruned((*C.btck_ChainstateManager)(cm.ptr)) != 0
+}
+
+// SnapshotChain takes an immutable snapshot of the active chain. The chain is copied
+// while the kernel holds its chainstate lock, so the snapshot is consistent even if
+// another goroutine is processing blocks at the same time.
+//
+// Taking a snapshot copies 32 bytes per block of the chain, so it should be reused
+// rather than taken for every read, e.g. refreshed from an OnBlockTip notification.
+//
+// Returns an error if the snapshot cannot be taken.
+func (cm *ChainstateManager) SnapshotChain() (*ChainSnapshot, error) {
+	if err := cm.fatalError("snapshot chain"); err != nil {
+		return nil, err
+	}
+	raw, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_chainstate_manager_snapshot_active_chain((*C.btck_ChainstateManager)(cm.ptr), writer, userData)
+	})
+	if !ok {
+		return nil, &Error{Op: "snapshot chain", Code: ErrorCodeInternal}
+	}
+	snapshot, err := decodeChainSnapshot(raw)
+	if err != nil {
+		return nil, &Error{Op: "snapshot chain", Code: ErrorCodeDeserialization, Err: err}
+	}
+	return snapshot, nil
+}
+
+// readError classifies a failure to read the block or undo data of entry from disk.
+func (cm *ChainstateManager) readError(op string, entry *BlockTreeEntry, undo bool) *Error {
+	code := ErrorCodeInternal
+	switch {
+	case !entry.HasBlockData() && cm.HavePruned():
+		code = ErrorCodeBlockPruned
+	case !entry.HasBlockData():
+		code = ErrorCodeBlockNotFound
+	case undo && !entry.HasUndoData():
+		code = ErrorCodeUndoMissing
+	}
+	ref := newBlockRef(entry)
+	return &Error{Op: op, Code: code, Block: &ref}
+}
+
 // ProcessBlock processes and validates the passed in block with the chainstate
 // manager. Processing first does checks on the block, and if these passed,
 // saves it to disk. It then validates the block against the utxo set. If it is
@@ -85,8 +150,13 @@ func (cm *ChainstateManager) ReadBlockSpentOutputs(blockTreeEntry *BlockTreeEntr
 // Returns ok=true if processing the block was successful (will also return true for valid,
 // but duplicate blocks) and duplicate=false if this block was not processed before. Note that
 // duplicate might also be false if processing was attempted before, but the block was found
-// invalid before its data was persisted.
+// invalid before its data was persisted. Returns ok=false without processing the block if
+// the kernel reported a fatal error before, see Err.
 func (cm *ChainstateManager) ProcessBlock(block *Block) (ok bool, duplicate bool) {
+	if cm.fatalError("process block") != nil {
+		return false, false
+	}
+
 	var newBlock C.int
 	result := C.btck_chainstate_manager_process_block((*C.btck_ChainstateManager)(cm.ptr), (*C.btck_Block)(block.ptr), &newBlock)
 	ok = result == 0
@@ -94,6 +164,92 @@ func (cm *ChainstateManager) ProcessBlock(block *Block) (ok bool, duplicate bool
 	return
 }
 
+// ProcessBlockContext is like ProcessBlock, but interrupts the validation when ctx is
+// done.
+//
+// See ImportBlocksContext for how cancellation is delivered and the state the chainstate
+// manager is left in afterwards.
+//
+// Parameters:
+//   - ctx: Go context whose cancellation interrupts processing
+//   - block: Block to validate and potentially add to the chain
+//
+// Returns an error with code ErrorCodeInterrupted wrapping ctx.Err() if ctx was done
+// before or while the block was processed, or with code ErrorCodeFatal if the kernel
+// reported a fatal error before or while the block was processed.
+func (cm *ChainstateManager) ProcessBlockContext(ctx context.Context, block *Block) (ok bool, duplicate bool, err error) {
+	err = cm.runInterruptible(ctx, "process block", func() error {
+		ok, duplicate = cm.ProcessBlock(block)
+		return cm.fatalError("process block")
+	})
+	return
+}
+
+// BlockValidationResultInfo describes the outcome of processing a block with
+// ProcessBlockWithResult.
+type BlockValidationResultInfo struct {
+	Checked          bool                  // Whether the block was fully validated while it was processed
+	ValidationMode   ValidationMode        // Validation mode of the block, only meaningful if Checked
+	ValidationResult BlockValidationResult // Reason the block was rejected, only meaningful if Checked
+	Duplicate        bool                  // Whether the block was processed before and not checked again
+	TipChanged       bool                  // Whether the block was connected to the active chain while it was processed, moving its tip
+}
+
+// Accepted reports whether the block was validated and found valid.
+func (info BlockValidationResultInfo) Accepted() bool {
+	return info.Checked && info.ValidationMode == ValidationStateValid
+}
+
+// ProcessBlockWithResult is like ProcessBlock, but also returns the result of the
+// block's validation, as it would otherwise be reported to the block_checked callback
+// of the validation interface.
+//
+// A block is only checked if it is new and either fails the initial checks or is
+// connected to the best chain. Checked is false for duplicate blocks and for blocks
+// that are stored without being connected, e.g. blocks on a side chain. A block that
+// fails the initial checks is not stored, but is checked and thus not a duplicate.
+// TipChanged is only set if this block was connected, so blocks processed concurrently
+// do not affect it.
+//
+// Parameters:
+//   - block: Block to validate and potentially add to the chain
+//
+// Returns an error if processing failed for a reason other than the block being
+// found invalid. An invalid block is reported through the returned info instead. The
+// error has code ErrorCodeFatal if the kernel reported a fatal error before or while the
+// block was processed.
+func (cm *ChainstateManager) ProcessBlockWithResult(block *Block) (BlockValidationResultInfo, error) {
+	if err := cm.fatalError("process block"); err != nil {
+		return BlockValidationResultInfo{}, err
+	}
+
+	var newBlock, checked, connected C.int
+	var mode C.btck_ValidationMode
+	var result C.btck_BlockValidationResult
+	ret := C.btck_chainstate_manager_process_block_with_result((*C.btck_ChainstateManager)(cm.ptr), (*C.btck_Block)(block.ptr),
+		&newBlock, &checked, &mode, &result, &connected)
+
+	info := BlockValidationResultInfo{
+		Checked:    checked != 0,
+		Duplicate:  checked == 0 && newBlock == 0,
+		TipChanged: connected != 0,
+	}
+	if info.Checked {
+		info.ValidationMode = ValidationMode(mode)
+		info.ValidationResult = BlockValidationResult(result)
+	}
+
+	if err := cm.fatalError("process block"); err != nil {
+		return info, err
+	}
+	if ret != 0 && !(info.Checked && info.ValidationMode == ValidationStateInvalid) {
+		hash := block.Hash()
+		defer hash.Destroy()
+		return info, &Error{Op: "process block", Code: ErrorCodeInternal, Block: &BlockRef{Hash: hash.Bytes(), Height: -1}}
+	}
+	return info, nil
+}
+
 // GetActiveChain returns the currently active best-known chain.
 //
 // The returned Chain can be thought of as a view on a vector of block tree entries
@@ -102,7 +258,7 @@ func (cm *ChainstateManager) ProcessBlock(block *Block) (ok bool, duplicate bool
 // from it is only consistent until new data is processed. It is the caller's responsibility
 // to guard against these inconsistencies.
 func (cm *ChainstateManager) GetActiveChain() *Chain {
-	return &Chain{C.btck_chainstate_manager_get_active_chain((*C.btck_ChainstateManager)(cm.ptr))}
+	return &Chain{C.btck_chainstate_manager_get_active_chain((*C.btck_ChainstateManager)(cm.ptr)), cm.uniqueHandle}
 }
 
 // GetBlockTreeEntryByHash retrieves a block tree entry by its block hash.
@@ -118,7 +274,76 @@ func (cm *ChainstateManager) GetBlockTreeEntryByHash(blockHash BlockHashLike) *B
 	if ptr == nil {
 		return nil
 	}
-	return &BlockTreeEntry{ptr: ptr}
+	return &BlockTreeEntry{ptr: ptr, owner: cm.uniqueHandle}
+}
+
+// GetScriptFlagsForBlock returns the script verification flags that consensus
+// enforces for the scripts of the block the block tree entry points to.
+//
+// The flags account for the buried deployment heights and the historical script
+// flag exceptions (e.g. the BIP16 exception block) of the configured chain, so they
+// can be passed to ScriptPubkey.Verify when re-verifying historical spends.
+//
+// Parameters:
+//   - blockTreeEntry: Block index entry of the block whose script flags to retrieve
+func (cm *ChainstateManager) GetScriptFlagsForBlock(blockTreeEntry *BlockTreeEntry) ScriptFlags {
+	flags := C.btck_chainstate_manager_get_block_script_flags((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.cptr())
+	return ScriptFlags(flags)
+}
+
+// GetDeploymentInfo returns the state of a deployment at the block the block tree
+// entry points to.
+//
+// The state follows the semantics of the getdeploymentinfo RPC: a deployment is
+// reported as active if its rules are enforced for the block following the entry.
+//
+// Parameters:
+//   - blockTreeEntry: Block index entry at which to query the deployment state
+//   - deployment: Deployment to query
+//
+// Returns an error with code ErrorCodeInvalidArgument wrapping
+// ErrKernelDeploymentNotEnabled if the deployment is not enabled on the configured chain.
+func (cm *ChainstateManager) GetDeploymentInfo(blockTreeEntry *BlockTreeEntry, deployment Deployment) (*DeploymentInfo, error) {
+	var info C.btck_DeploymentInfo
+	result := C.btck_chainstate_manager_get_deployment_info((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.cptr(), deployment.c(), &info)
+	if result != 0 {
+		ref := newBlockRef(blockTreeEntry)
+		return nil, &Error{Op: "get deployment info", Code: ErrorCodeInvalidArgument, Block: &ref, Err: ErrKernelDeploymentNotEnabled}
+	}
+	return newDeploymentInfo(deployment, &info), nil
+}
+
+// GetDeploymentInfos returns the state of all deployments enabled on the configured
+// chain at the block the block tree entry points to, in the order of Deployments.
+//
+// Parameters:
+//   - blockTreeEntry: Block index entry at which to query the deployment states
+func (cm *ChainstateManager) GetDeploymentInfos(blockTreeEntry *BlockTreeEntry) []*DeploymentInfo {
+	var infos []*DeploymentInfo
+	for _, deployment := range Deployments {
+		info, err := cm.GetDeploymentInfo(blockTreeEntry, deployment)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// GetNextWorkRequired returns the proof of work target, in compact form, that a
+// block building on the block the block tree entry points to must commit to in its
+// nBits field.
+//
+// The difficulty adjustment rules of the configured chain are applied, including
+// the retargeting interval, the minimum difficulty exception of test networks and
+// the disabled retargeting of regtest.
+//
+// Parameters:
+//   - prev: Block index entry of the block the new block builds on
+//   - headerTime: Timestamp of the new block header, in seconds since the Unix epoch.
+//     Only relevant on chains that allow minimum difficulty blocks
+func (cm *ChainstateManager) GetNextWorkRequired(prev *BlockTreeEntry, headerTime int64) uint32 {
+	return uint32(C.btck_chainstate_manager_get_next_work_required((*C.btck_ChainstateManager)(cm.ptr), prev.cptr(), C.int64_t(headerTime)))
 }
 
 // ImportBlocks triggers a reindex and/or imports block files from the filesystem.
@@ -130,8 +355,14 @@ func (cm *ChainstateManager) GetBlockTreeEntryByHash(blockHash BlockHashLike) *B
 //   - blockFilePaths: Array of full filesystem paths to block files to import (can be empty)
 //
 // Returns an error if the import fails. This is a long-running operation that can
-// be interrupted via Context.Interrupt().
+// be interrupted via Context.Interrupt(), see ImportBlocksContext for a variant that
+// observes Go context cancellation. The error has code ErrorCodeFatal if the kernel
+// reported a fatal error before or during the import, which also interrupts it.
 func (cm *ChainstateManager) ImportBlocks(blockFilePaths []string) error {
+	if err := cm.fatalError("import blocks"); err != nil {
+		return err
+	}
+
 	// Convert Go strings to C strings
 	cPaths := make([]*C.char, len(blockFilePaths))
 	cLens := make([]C.size_t, len(blockFilePaths))
@@ -162,8 +393,99 @@ func (cm *ChainstateManager) ImportBlocks(blockFilePaths []string) error {
 		cLensPtr,
 		C.size_t(len(blockFilePaths)),
 	)
+	if err := cm.fatalError("import blocks"); err != nil {
+		return err
+	}
 	if success != 0 {
-		return &InternalError{"Failed to import blocks"}
+		return &Error{Op: "import blocks", Code: ErrorCodeInternal}
+	}
+	return nil
+}
+
+// ImportBlocksContext is like ImportBlocks, but interrupts the import when ctx is done.
+//
+// Cancellation is delivered by interrupting the kernel context the chainstate manager was
+// created with, so it also interrupts other long-running operations running concurrently
+// on chainstate managers sharing that context. Once the import has returned, the interrupt
+// is reset and the chainstate manager can be used again: blocks imported before the
+// interruption are kept, and calling ImportBlocksContext again with the same paths resumes
+// the import or reindex. The interrupt is kept if the kernel context was also interrupted
+// with Context.Interrupt, e.g. by Node.Close, or failed, see ErrorPolicy.
+//
+// Parameters:
+//   - ctx: Go context whose cancellation interrupts the import
+//   - blockFilePaths: Array of full filesystem paths to block files to import (can be empty)
+//
+// Returns an error with code ErrorCodeInterrupted wrapping ctx.Err() if ctx was done
+// before or during the import, or an error if the import fails.
+func (cm *ChainstateManager) ImportBlocksContext(ctx context.Context, blockFilePaths []string) error {
+	return cm.runInterruptible(ctx, "import blocks", func() error {
+		return cm.ImportBlocks(blockFilePaths)
+	})
+}
+
+// runInterruptible runs fn, interrupting the kernel context if ctx is done before fn
+// returns. The interrupt is reset before returning so that later operations are not
+// affected by it, unless the kernel context was interrupted for another reason as well.
+// If fn returned before the interrupt was delivered, its result is returned.
+func (cm *ChainstateManager) runInterruptible(ctx context.Context, op string, fn func() error) error {
+	if err := cm.fatalError(op); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return &Error{Op: op, Code: ErrorCodeInterrupted, Err: err}
+	}
+
+	var (
+		mu           sync.Mutex
+		finished     bool
+		interrupted  bool
+		interruptErr error
+	)
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if finished {
+			return
+		}
+		interruptErr = cm.context.interruptForCancellation()
+		interrupted = interruptErr == nil
+	})
+
+	err := fn()
+	// Once finished is set, a pending interrupt is no longer delivered
+	mu.Lock()
+	finished = true
+	mu.Unlock()
+	stop()
+
+	if interruptErr != nil {
+		return interruptErr
+	}
+	if !interrupted {
+		return err
+	}
+	if resetErr := cm.context.finishCancellation(); resetErr != nil {
+		return resetErr
+	}
+	if err := cm.fatalError(op); err != nil {
+		return err
+	}
+	return &Error{Op: op, Code: ErrorCodeInterrupted, Err: ctx.Err()}
+}
+
+// Err returns the *FatalKernelError that failed the context of the chainstate manager,
+// or nil if the kernel has not reported a fatal error, see ErrorPolicy. Once it returns
+// an error, the chainstate manager refuses to process blocks and read block data.
+func (cm *ChainstateManager) Err() error {
+	return cm.context.Err()
+}
+
+// fatalError returns an Error with code ErrorCodeFatal for op if the context of the
+// chainstate manager has failed, or nil.
+func (cm *ChainstateManager) fatalError(op string) error {
+	if err := cm.context.failure.failure(); err != nil {
+		return &Error{Op: op, Code: ErrorCodeFatal, Err: err}
 	}
 	return nil
 }
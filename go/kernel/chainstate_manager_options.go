@@ -21,11 +21,18 @@ func (chainstateManagerOptionsCFuncs) destroy(ptr unsafe.Pointer) {
 // setter methods before creating the chainstate manager.
 type ChainstateManagerOptions struct {
 	*uniqueHandle
+	context *Context
 }
 
-func newChainstateManagerOptions(ptr *C.btck_ChainstateManagerOptions) *ChainstateManagerOptions {
+func newChainstateManagerOptions(ptr *C.btck_ChainstateManagerOptions, context *Context) *ChainstateManagerOptions {
 	h := newUniqueHandle(unsafe.Pointer(ptr), chainstateManagerOptionsCFuncs{})
-	return &ChainstateManagerOptions{uniqueHandle: h}
+	return &ChainstateManagerOptions{uniqueHandle: h, context: context}
+}
+
+// Destroy destroys the options and releases their reference to the kernel context.
+func (opts *ChainstateManagerOptions) Destroy() {
+	opts.uniqueHandle.Destroy()
+	opts.context.Destroy()
 }
 
 // NewChainstateManagerOptions creates options for configuring a chainstate manager.
@@ -49,9 +56,9 @@ func NewChainstateManagerOptions(context *Context, dataDir, blocksDir string) (*
 	ptr := C.btck_chainstate_manager_options_create((*C.btck_Context)(context.ptr), cDataDir, C.size_t(len(dataDir)),
 		cBlocksDir, C.size_t(len(blocksDir)))
 	if ptr == nil {
-		return nil, &InternalError{"Failed to create chainstate manager options"}
+		return nil, &Error{Op: "create chainstate manager options", Code: ErrorCodeInternal}
 	}
-	return newChainstateManagerOptions(ptr), nil
+	return newChainstateManagerOptions(ptr, context.Copy()), nil
 }
 
 // SetWorkerThreads configures the number of worker threads for parallel validation.
@@ -83,7 +90,7 @@ func (opts *ChainstateManagerOptions) SetWipeDBs(wipeBlockTree, wipeChainstate b
 	}
 	result := C.btck_chainstate_manager_options_set_wipe_dbs((*C.btck_ChainstateManagerOptions)(opts.ptr), C.int(wipeBlockTreeInt), C.int(wipeChainstateInt))
 	if result != 0 {
-		return &InternalError{"Failed to set wipe db"}
+		return &Error{Op: "set wipe dbs", Code: ErrorCodeInvalidArgument}
 	}
 	return nil
 }
@@ -1,11 +1,19 @@
 package kernel
 
 import (
+	"bytes"
+	"context"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestChainstateManager(t *testing.T) {
@@ -17,8 +25,18 @@ func TestChainstateManager(t *testing.T) {
 	suite.Setup(t)
 
 	t.Run("read block", suite.TestReadBlock)
+	t.Run("decode blocks", suite.TestDecodeBlocks)
 	t.Run("block undo", suite.TestBlockSpentOutputs)
+	t.Run("block undo bytes", suite.TestBlockSpentOutputsBytes)
+	t.Run("block undo iterators", suite.TestBlockSpentOutputsIterators)
 	t.Run("get block tree entry by hash", suite.TestGetBlockTreeEntryByHash)
+	t.Run("script flags for block", suite.TestGetScriptFlagsForBlock)
+	t.Run("deployment info", suite.TestGetDeploymentInfo)
+	t.Run("next work required", suite.TestGetNextWorkRequired)
+	t.Run("context cancellation", suite.TestContextCancellation)
+	t.Run("block data availability", suite.TestBlockDataAvailability)
+	t.Run("snapshot chain", suite.TestSnapshotChain)
+	t.Run("verify block scripts", suite.TestVerifyBlockScripts)
 }
 
 func (s *ChainstateManagerTestSuite) TestBlockSpentOutputs(t *testing.T) {
@@ -64,6 +82,167 @@ func (s *ChainstateManagerTestSuite) TestBlockSpentOutputs(t *testing.T) {
 	}
 }
 
+func (s *ChainstateManagerTestSuite) TestBlockSpentOutputsBytes(t *testing.T) {
+	chain := s.Manager.GetActiveChain()
+
+	blockSpentOutputs, err := s.Manager.ReadBlockSpentOutputs(chain.GetByHeight(202))
+	if err != nil {
+		t.Fatalf("ReadBlockSpentOutputs() error = %v", err)
+	}
+	defer blockSpentOutputs.Destroy()
+
+	raw, err := blockSpentOutputs.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+
+	parsed, err := NewBlockSpentOutputs(raw)
+	if err != nil {
+		t.Fatalf("NewBlockSpentOutputs() error = %v", err)
+	}
+	defer parsed.Destroy()
+
+	if parsed.Count() != blockSpentOutputs.Count() {
+		t.Fatalf("Count() = %d, want %d", parsed.Count(), blockSpentOutputs.Count())
+	}
+	for i := uint64(0); i < parsed.Count(); i++ {
+		want, err := blockSpentOutputs.GetTransactionSpentOutputsAt(i)
+		if err != nil {
+			t.Fatalf("GetTransactionSpentOutputsAt(%d) error = %v", i, err)
+		}
+		got, err := parsed.GetTransactionSpentOutputsAt(i)
+		if err != nil {
+			t.Fatalf("GetTransactionSpentOutputsAt(%d) error = %v", i, err)
+		}
+		wantCoin, err := want.GetCoinAt(0)
+		if err != nil {
+			t.Fatalf("GetCoinAt(0) error = %v", err)
+		}
+		gotCoin, err := got.GetCoinAt(0)
+		if err != nil {
+			t.Fatalf("GetCoinAt(0) error = %v", err)
+		}
+		if gotCoin.ConfirmationHeight() != wantCoin.ConfirmationHeight() || gotCoin.IsCoinbase() != wantCoin.IsCoinbase() {
+			t.Errorf("Coin %d mismatch after round trip", i)
+		}
+		if gotCoin.GetOutput().Amount() != wantCoin.GetOutput().Amount() {
+			t.Errorf("Coin %d amount = %d, want %d", i, gotCoin.GetOutput().Amount(), wantCoin.GetOutput().Amount())
+		}
+	}
+
+	reserialized, err := parsed.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(reserialized, raw) {
+		t.Errorf("Round-tripped undo data differs from original")
+	}
+
+	// The genesis block has no spent outputs, serialized as an empty vector
+	genesisSpentOutputs, err := s.Manager.ReadBlockSpentOutputs(chain.GetGenesis())
+	if err != nil {
+		t.Fatalf("ReadBlockSpentOutputs(genesis) error = %v", err)
+	}
+	defer genesisSpentOutputs.Destroy()
+	genesisRaw, err := genesisSpentOutputs.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(genesisRaw, []byte{0x00}) {
+		t.Errorf("Genesis undo bytes = %x, want 00", genesisRaw)
+	}
+
+	// Truncated undo data must be rejected
+	if _, err := NewBlockSpentOutputs(raw[:len(raw)-1]); err == nil {
+		t.Errorf("NewBlockSpentOutputs() with truncated data expected error, got nil")
+	}
+	if _, err := NewBlockSpentOutputs(nil); err == nil {
+		t.Errorf("NewBlockSpentOutputs() with empty data expected error, got nil")
+	}
+}
+
+func (s *ChainstateManagerTestSuite) TestBlockSpentOutputsIterators(t *testing.T) {
+	blockTreeEntry := s.Manager.GetActiveChain().GetByHeight(202)
+
+	block, err := s.Manager.ReadBlock(blockTreeEntry)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	blockSpentOutputs, err := s.Manager.ReadBlockSpentOutputs(blockTreeEntry)
+	if err != nil {
+		t.Fatalf("ReadBlockSpentOutputs() error = %v", err)
+	}
+	defer blockSpentOutputs.Destroy()
+
+	var txCount uint64
+	for i, txSpentOutputs := range blockSpentOutputs.Transactions() {
+		// Spent outputs skip the coinbase transaction
+		tx, err := block.GetTransactionAt(i + 1)
+		if err != nil {
+			t.Fatalf("GetTransactionAt(%d) error = %v", i+1, err)
+		}
+		if txSpentOutputs.Count() != tx.CountInputs() {
+			t.Errorf("Transaction %d has %d spent outputs, want %d", i+1, txSpentOutputs.Count(), tx.CountInputs())
+		}
+
+		var coinCount uint64
+		for j, coin := range txSpentOutputs.Coins() {
+			if j != coinCount {
+				t.Errorf("Coins() yielded index %d, want %d", j, coinCount)
+			}
+			if coin.ConfirmationHeight() <= 0 {
+				t.Errorf("ConfirmationHeight() = %d, want > 0", coin.ConfirmationHeight())
+			}
+			coinCount++
+		}
+		if coinCount != txSpentOutputs.Count() {
+			t.Errorf("Coins() yielded %d coins, want %d", coinCount, txSpentOutputs.Count())
+		}
+		txCount++
+	}
+	if txCount != blockSpentOutputs.Count() {
+		t.Errorf("Transactions() yielded %d entries, want %d", txCount, blockSpentOutputs.Count())
+	}
+}
+
+func (s *ChainstateManagerTestSuite) TestDecodeBlocks(t *testing.T) {
+	var witnessTxCount int
+	for entry := range s.Manager.GetActiveChain().Entries(0, s.ImportedBlocksCount) {
+		block, err := s.Manager.ReadBlock(entry)
+		if err != nil {
+			t.Fatalf("ReadBlock() error = %v", err)
+		}
+		defer block.Destroy()
+
+		decoded, err := block.Decode()
+		if err != nil {
+			t.Fatalf("Decode() at height %d error = %v", entry.Height(), err)
+		}
+		if decoded.Hash != entry.Hash().Bytes() {
+			t.Errorf("Decoded hash at height %d = %x, want %x", entry.Height(), decoded.Hash, entry.Hash().Bytes())
+		}
+		raw, err := block.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		if !bytes.Equal(decoded.Bytes(), raw) {
+			t.Errorf("Decoded block at height %d does not round-trip", entry.Height())
+		}
+
+		for i, tx := range block.Transactions() {
+			assertDecodedTransaction(t, decoded.Transactions[i], &tx.transactionApi)
+			if decoded.Transactions[i].HasWitness() {
+				witnessTxCount++
+			}
+		}
+	}
+	if witnessTxCount == 0 {
+		t.Error("Expected the regtest chain to contain transactions with witness data")
+	}
+}
+
 func (s *ChainstateManagerTestSuite) TestReadBlock(t *testing.T) {
 	chain := s.Manager.GetActiveChain()
 
@@ -129,6 +308,74 @@ func (s *ChainstateManagerTestSuite) TestGetBlockTreeEntryByHash(t *testing.T) {
 	}
 }
 
+func (s *ChainstateManagerTestSuite) TestGetScriptFlagsForBlock(t *testing.T) {
+	chain := s.Manager.GetActiveChain()
+
+	// On regtest all buried deployments except segwit activate at height 1
+	genesisFlags := s.Manager.GetScriptFlagsForBlock(chain.GetGenesis())
+	wantGenesisFlags := ScriptFlagsVerifyP2SH | ScriptFlagsVerifyWitness | ScriptFlagsVerifyTaproot | ScriptFlagsVerifyNullDummy
+	if genesisFlags != wantGenesisFlags {
+		t.Errorf("GetScriptFlagsForBlock(genesis) = %#x, want %#x", genesisFlags, wantGenesisFlags)
+	}
+
+	tipFlags := s.Manager.GetScriptFlagsForBlock(chain.GetTip())
+	if tipFlags != ScriptFlagsVerifyAll {
+		t.Errorf("GetScriptFlagsForBlock(tip) = %#x, want %#x", tipFlags, ScriptFlagsVerifyAll)
+	}
+}
+
+func (s *ChainstateManagerTestSuite) TestGetDeploymentInfo(t *testing.T) {
+	tip := s.Manager.GetActiveChain().GetTip()
+
+	infos := s.Manager.GetDeploymentInfos(tip)
+	if len(infos) != len(Deployments) {
+		t.Fatalf("GetDeploymentInfos() returned %d deployments, want %d", len(infos), len(Deployments))
+	}
+
+	// Buried deployments are always active on regtest
+	segwit, err := s.Manager.GetDeploymentInfo(tip, DeploymentSegwit)
+	if err != nil {
+		t.Fatalf("GetDeploymentInfo(segwit) error = %v", err)
+	}
+	if !segwit.Buried || !segwit.Active || segwit.Height != 0 || segwit.BIP9 != nil {
+		t.Errorf("Unexpected segwit deployment info %+v", segwit)
+	}
+
+	// Taproot is configured as always active on regtest
+	taproot, err := s.Manager.GetDeploymentInfo(tip, DeploymentTaproot)
+	if err != nil {
+		t.Fatalf("GetDeploymentInfo(taproot) error = %v", err)
+	}
+	if taproot.Buried || !taproot.Active || taproot.BIP9 == nil {
+		t.Fatalf("Unexpected taproot deployment info %+v", taproot)
+	}
+	if taproot.BIP9.State != DeploymentStateActive || taproot.BIP9.Statistics != nil {
+		t.Errorf("Unexpected taproot versionbits info %+v", taproot.BIP9)
+	}
+
+	// The test dummy deployment starts signalling in the second period and is never signalled for
+	dummy, err := s.Manager.GetDeploymentInfo(tip, DeploymentTestDummy)
+	if err != nil {
+		t.Fatalf("GetDeploymentInfo(testdummy) error = %v", err)
+	}
+	if dummy.Active || dummy.Height != -1 || dummy.BIP9 == nil {
+		t.Fatalf("Unexpected testdummy deployment info %+v", dummy)
+	}
+	if dummy.BIP9.Bit != 28 || dummy.BIP9.State != DeploymentStateStarted || dummy.BIP9.Since != 144 {
+		t.Errorf("Unexpected testdummy versionbits info %+v", dummy.BIP9)
+	}
+	stats := dummy.BIP9.Statistics
+	if stats == nil {
+		t.Fatal("Expected signalling statistics for started deployment")
+	}
+	if stats.Period != 144 || stats.Threshold != 108 || stats.Count != 0 {
+		t.Errorf("Unexpected testdummy statistics %+v", stats)
+	}
+	if stats.Elapsed != uint32(tip.Height()%144+1) {
+		t.Errorf("Expected %d elapsed blocks, got %d", tip.Height()%144+1, stats.Elapsed)
+	}
+}
+
 type ChainstateManagerTestSuite struct {
 	MaxBlockHeightToImport int32 // leave zero to load all blocks
 	NotificationCallbacks  *NotificationCallbacks
@@ -200,7 +447,317 @@ func (s *ChainstateManagerTestSuite) Setup(t *testing.T) {
 		t.Fatalf("ImportBlocks() error = %v", err)
 	}
 
-	// Load block data from data/regtest/blocks.txt
+	blockLines := regtestBlockLines(t)
+	if s.MaxBlockHeightToImport != 0 && len(blockLines) > int(s.MaxBlockHeightToImport) {
+		blockLines = blockLines[:s.MaxBlockHeightToImport]
+	}
+
+	for i := 0; i < len(blockLines); i++ {
+		blockHex := blockLines[i]
+
+		blockBytes, err := hex.DecodeString(blockHex)
+		if err != nil {
+			t.Fatalf("Failed to decode block %d hex: %v", i+1, err)
+		}
+
+		block, err := NewBlock(blockBytes)
+		if err != nil {
+			t.Fatalf("NewBlockFromRaw() failed for block %d: %v", i+1, err)
+		}
+		defer block.Destroy()
+
+		ok, duplicate := manager.ProcessBlock(block)
+		if !ok || duplicate {
+			t.Fatalf("ProcessBlock() failed for block %d", i+1)
+		}
+	}
+
+	s.Manager = manager
+	s.ImportedBlocksCount = int32(len(blockLines))
+}
+
+func TestProcessBlockWithResult(t *testing.T) {
+	suite := ChainstateManagerTestSuite{MaxBlockHeightToImport: 2}
+	suite.Setup(t)
+	manager := suite.Manager
+
+	block, err := NewBlock(mustDecodeHex(t, regtestBlockLines(t)[2]))
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	// A block whose transactions do not match the merkle root in its header fails
+	// the initial checks under the same block hash
+	decoded, err := block.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	decoded.Transactions[0].Outputs[0].Amount--
+	mutated, err := NewBlock(decoded.Bytes())
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	defer mutated.Destroy()
+
+	info, err := manager.ProcessBlockWithResult(mutated)
+	if err != nil {
+		t.Fatalf("ProcessBlockWithResult() error = %v", err)
+	}
+	want := BlockValidationResultInfo{
+		Checked:          true,
+		ValidationMode:   ValidationStateInvalid,
+		ValidationResult: BlockMutated,
+	}
+	if info != want {
+		t.Errorf("ProcessBlockWithResult() for mutated block = %+v, want %+v", info, want)
+	}
+
+	info, err = manager.ProcessBlockWithResult(block)
+	if err != nil {
+		t.Fatalf("ProcessBlockWithResult() error = %v", err)
+	}
+	want = BlockValidationResultInfo{
+		Checked:          true,
+		ValidationMode:   ValidationStateValid,
+		ValidationResult: BlockResultUnset,
+		TipChanged:       true,
+	}
+	if info != want || !info.Accepted() {
+		t.Errorf("ProcessBlockWithResult() for new block = %+v, want %+v", info, want)
+	}
+	if height := manager.GetActiveChain().GetHeight(); height != 3 {
+		t.Errorf("Chain height = %d, want 3", height)
+	}
+
+	info, err = manager.ProcessBlockWithResult(block)
+	if err != nil {
+		t.Fatalf("ProcessBlockWithResult() error = %v", err)
+	}
+	if want := (BlockValidationResultInfo{Duplicate: true}); info != want {
+		t.Errorf("ProcessBlockWithResult() for duplicate block = %+v, want %+v", info, want)
+	}
+}
+
+func (s *ChainstateManagerTestSuite) TestGetNextWorkRequired(t *testing.T) {
+	tip := s.Manager.GetActiveChain().GetTip()
+
+	// Regtest disables retargeting, so every block commits to the proof of work limit
+	const regtestPowLimitBits = 0x207fffff
+	bits := s.Manager.GetNextWorkRequired(tip, time.Now().Unix())
+	if bits != regtestPowLimitBits {
+		t.Errorf("GetNextWorkRequired() = %#x, want %#x", bits, regtestPowLimitBits)
+	}
+	if work := CompactToWork(bits); work.Int64() != 2 {
+		t.Errorf("CompactToWork(%#x) = %s, want 2", bits, work)
+	}
+}
+
+func (s *ChainstateManagerTestSuite) TestContextCancellation(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := s.Manager.ImportBlocksContext(canceled, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ImportBlocksContext() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+	if !errors.Is(err, ErrInterrupted) {
+		t.Errorf("ImportBlocksContext() error = %v, want %v", err, ErrInterrupted)
+	}
+
+	// An interrupt delivered through the kernel context is cleared by ResetInterrupt
+	if err := s.Manager.context.Interrupt(); err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+	if err := s.Manager.context.ResetInterrupt(); err != nil {
+		t.Fatalf("ResetInterrupt() error = %v", err)
+	}
+
+	// The manager remains usable afterwards
+	if err := s.Manager.ImportBlocksContext(context.Background(), nil); err != nil {
+		t.Fatalf("ImportBlocksContext() error = %v", err)
+	}
+
+	tip := s.Manager.GetActiveChain().GetTip()
+	block, err := s.Manager.ReadBlock(tip)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	ok, duplicate, err := s.Manager.ProcessBlockContext(context.Background(), block)
+	if err != nil {
+		t.Fatalf("ProcessBlockContext() error = %v", err)
+	}
+	if !ok || !duplicate {
+		t.Errorf("ProcessBlockContext() = (%v, %v), want (true, true)", ok, duplicate)
+	}
+
+	if _, _, err := s.Manager.ProcessBlockContext(canceled, block); !errors.Is(err, context.Canceled) {
+		t.Errorf("ProcessBlockContext() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+	if got := s.Manager.GetActiveChain().GetTip().Height(); got != tip.Height() {
+		t.Errorf("Tip height after cancellation = %d, want %d", got, tip.Height())
+	}
+}
+
+func TestImportBlocksContextInterruptedMidway(t *testing.T) {
+	dataDir := t.TempDir()
+	blockLines := regtestBlockLines(t)
+	const height = 50
+
+	node, err := OpenNode(dataDir, WithChain(ChainTypeRegtest), WithWorkerThreads(1))
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+	for i := 0; i < height; i++ {
+		block, err := NewBlock(mustDecodeHex(t, blockLines[i]))
+		if err != nil {
+			t.Fatalf("NewBlock() error = %v", err)
+		}
+		ok, _ := node.ChainstateManager().ProcessBlock(block)
+		block.Destroy()
+		if !ok {
+			t.Fatalf("ProcessBlock() failed for block %d", i+1)
+		}
+	}
+	if err := node.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// reindex starts a reindex of the stored blocks that is cancelled from the first
+	// block tip notification, once the interrupt has been delivered. If interrupt is
+	// set, the kernel context is also interrupted explicitly.
+	reindex := func(t *testing.T, interrupt bool) *ChainstateManager {
+		importCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var manager *ChainstateManager
+		var once sync.Once
+		var armed atomic.Bool
+		notifications := &NotificationCallbacks{
+			OnBlockTip: func(state SynchronizationState, entry *BlockTreeEntry, progress float64) {
+				if !armed.Load() {
+					return
+				}
+				once.Do(func() {
+					if interrupt {
+						if err := manager.context.Interrupt(); err != nil {
+							t.Errorf("Interrupt() error = %v", err)
+						}
+					}
+					cancel()
+					waitForCancellationInterrupt(t, manager.context)
+				})
+			},
+		}
+		manager = newReindexingManager(t, dataDir, notifications)
+
+		armed.Store(true)
+		err := manager.ImportBlocksContext(importCtx, nil)
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrInterrupted) {
+			t.Fatalf("ImportBlocksContext() error = %v, want %v and %v", err, ErrInterrupted, context.Canceled)
+		}
+		if tip := manager.GetActiveChain().GetTip(); tip != nil && tip.Height() >= height {
+			t.Fatalf("Tip height after cancellation = %d, want < %d", tip.Height(), height)
+		}
+		return manager
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		manager := reindex(t, false)
+		// The interrupt was reset, so the reindex resumes
+		if err := manager.ImportBlocksContext(context.Background(), nil); err != nil {
+			t.Fatalf("ImportBlocksContext() error = %v", err)
+		}
+		if got := manager.GetActiveChain().GetTip().Height(); got != height {
+			t.Errorf("Tip height after resuming = %d, want %d", got, height)
+		}
+	})
+
+	t.Run("explicit interrupt", func(t *testing.T) {
+		manager := reindex(t, true)
+		tipHeight := int32(-1)
+		if tip := manager.GetActiveChain().GetTip(); tip != nil {
+			tipHeight = tip.Height()
+		}
+
+		// The explicit interrupt is kept, so the reindex makes no progress until it is reset
+		_ = manager.ImportBlocks(nil)
+		if tip := manager.GetActiveChain().GetTip(); tip != nil && tip.Height() != tipHeight {
+			t.Errorf("Tip height while interrupted = %d, want %d", tip.Height(), tipHeight)
+		}
+		if err := manager.context.ResetInterrupt(); err != nil {
+			t.Fatalf("ResetInterrupt() error = %v", err)
+		}
+		if err := manager.ImportBlocks(nil); err != nil {
+			t.Fatalf("ImportBlocks() error = %v", err)
+		}
+		if got := manager.GetActiveChain().GetTip().Height(); got != height {
+			t.Errorf("Tip height after reset = %d, want %d", got, height)
+		}
+	})
+}
+
+// newReindexingManager creates a regtest chainstate manager for dataDir whose next
+// import reindexes the stored blocks. It is destroyed when the test completes.
+func newReindexingManager(t *testing.T, dataDir string, notifications *NotificationCallbacks) *ChainstateManager {
+	t.Helper()
+	chainParams, err := NewChainParameters(ChainTypeRegtest)
+	if err != nil {
+		t.Fatalf("NewChainParameters() error = %v", err)
+	}
+	defer chainParams.Destroy()
+
+	contextOpts := NewContextOptions()
+	defer contextOpts.Destroy()
+	contextOpts.SetChainParams(chainParams)
+	contextOpts.SetNotifications(notifications)
+
+	ctx, err := NewContext(contextOpts)
+	if err != nil {
+		t.Fatalf("NewContext() error = %v", err)
+	}
+	defer ctx.Destroy()
+
+	opts, err := NewChainstateManagerOptions(ctx, dataDir, filepath.Join(dataDir, "blocks"))
+	if err != nil {
+		t.Fatalf("NewChainstateManagerOptions() error = %v", err)
+	}
+	defer opts.Destroy()
+	opts.SetWorkerThreads(1)
+	if err := opts.SetWipeDBs(true, true); err != nil {
+		t.Fatalf("SetWipeDBs() error = %v", err)
+	}
+
+	manager, err := NewChainstateManager(opts)
+	if err != nil {
+		t.Fatalf("NewChainstateManager() error = %v", err)
+	}
+	t.Cleanup(manager.Destroy)
+	return manager
+}
+
+// waitForCancellationInterrupt waits until an operation on ctx has delivered the
+// interrupt for its cancelled Go context.
+func waitForCancellationInterrupt(t *testing.T, ctx *Context) {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		ctx.interrupts.mu.Lock()
+		cancelled := ctx.interrupts.cancelled
+		ctx.interrupts.mu.Unlock()
+		if cancelled > 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Errorf("Timed out waiting for the cancellation interrupt")
+}
+
+// regtestBlockLines returns the hex encoded blocks of data/regtest/blocks.txt, the
+// block at height h being at index h-1.
+func regtestBlockLines(t *testing.T) []string {
+	t.Helper()
 	wd, err := os.Getwd()
 	if err != nil {
 		t.Fatalf("Failed to get working directory: %v", err)
@@ -219,34 +776,140 @@ func (s *ChainstateManagerTestSuite) Setup(t *testing.T) {
 		if line != "" {
 			blockLines = append(blockLines, line)
 		}
-		if s.MaxBlockHeightToImport != 0 && len(blockLines) >= int(s.MaxBlockHeightToImport) {
-			break
-		}
 	}
 	if len(blockLines) == 0 {
 		t.Fatal("No block data found in blocks.txt")
 	}
+	return blockLines
+}
 
-	for i := 0; i < len(blockLines); i++ {
-		blockHex := blockLines[i]
+func (s *ChainstateManagerTestSuite) TestBlockDataAvailability(t *testing.T) {
+	if s.Manager.HavePruned() {
+		t.Error("HavePruned() = true for an unpruned chainstate")
+	}
 
-		blockBytes, err := hex.DecodeString(blockHex)
-		if err != nil {
-			t.Fatalf("Failed to decode block %d hex: %v", i+1, err)
+	chain := s.Manager.GetActiveChain()
+	for _, entry := range []*BlockTreeEntry{chain.GetGenesis(), chain.GetTip()} {
+		if !entry.HasBlockData() {
+			t.Errorf("HasBlockData() = false at height %d", entry.Height())
 		}
+	}
+	if !chain.GetTip().HasUndoData() {
+		t.Error("HasUndoData() = false for the tip")
+	}
+}
 
-		block, err := NewBlock(blockBytes)
+func (s *ChainstateManagerTestSuite) TestSnapshotChain(t *testing.T) {
+	snapshot, err := s.Manager.SnapshotChain()
+	if err != nil {
+		t.Fatalf("SnapshotChain() error = %v", err)
+	}
+
+	chain := s.Manager.GetActiveChain()
+	if snapshot.Height() != chain.GetHeight() {
+		t.Fatalf("Height() = %d, want %d", snapshot.Height(), chain.GetHeight())
+	}
+	for entry := range chain.Entries(0, chain.GetHeight()) {
+		hash, ok := snapshot.Hash(entry.Height())
+		if !ok || hash != entry.Hash().Key() {
+			t.Fatalf("Hash(%d) = %s, want %s", entry.Height(), hash, entry.Hash())
+		}
+		if height, ok := snapshot.HeightOf(hash); !ok || height != entry.Height() {
+			t.Fatalf("HeightOf(%s) = %d, %v, want %d", hash, height, ok, entry.Height())
+		}
+	}
+
+	tip, ok := snapshot.Tip()
+	if !ok || tip != newBlockRef(chain.GetTip()) {
+		t.Errorf("Tip() = %v, %v, want %v", tip, ok, newBlockRef(chain.GetTip()))
+	}
+
+	// Every regtest block has the minimum difficulty
+	want := new(big.Int).Mul(CompactToWork(0x207fffff), big.NewInt(int64(snapshot.Height())+1))
+	if snapshot.Work().Cmp(want) != 0 {
+		t.Errorf("Work() = %v, want %v", snapshot.Work(), want)
+	}
+}
+
+func TestSnapshotChainConcurrentProcessing(t *testing.T) {
+	const initialHeight = 10
+	suite := ChainstateManagerTestSuite{MaxBlockHeightToImport: initialHeight}
+	suite.Setup(t)
+
+	blockLines := regtestBlockLines(t)
+	rawBlocks := make([][]byte, len(blockLines))
+	expected := make([]BlockHashKey, len(blockLines))
+	for i, line := range blockLines {
+		rawBlocks[i] = mustDecodeHex(t, line)
+		block, err := NewBlock(rawBlocks[i])
 		if err != nil {
-			t.Fatalf("NewBlockFromRaw() failed for block %d: %v", i+1, err)
+			t.Fatalf("NewBlock() error = %v", err)
 		}
-		defer block.Destroy()
+		hash := block.Hash()
+		expected[i] = hash.Key()
+		hash.Destroy()
+		block.Destroy()
+	}
 
-		ok, duplicate := manager.ProcessBlock(block)
-		if !ok || duplicate {
-			t.Fatalf("ProcessBlock() failed for block %d", i+1)
+	// The processing goroutine must have returned before the test fails, as it still
+	// uses the chainstate manager
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, raw := range rawBlocks[initialHeight:] {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			block, err := NewBlock(raw)
+			if err != nil {
+				t.Errorf("NewBlock() error = %v", err)
+				return
+			}
+			suite.Manager.ProcessBlock(block)
+			block.Destroy()
+		}
+	}()
+
+	check := func(snapshot *ChainSnapshot) error {
+		if snapshot.Height() < initialHeight {
+			return fmt.Errorf("Snapshot height %d below initial height %d", snapshot.Height(), initialHeight)
 		}
+		for height, hash := range snapshot.Range(1, snapshot.Height()) {
+			if hash != expected[height-1] {
+				return fmt.Errorf("Snapshot hash at height %d = %s, want %s", height, hash, expected[height-1])
+			}
+		}
+		return nil
 	}
 
-	s.Manager = manager
-	s.ImportedBlocksCount = int32(len(blockLines))
+	var failure error
+	for running := true; running && failure == nil; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		snapshot, err := suite.Manager.SnapshotChain()
+		if err != nil {
+			failure = fmt.Errorf("SnapshotChain() error = %v", err)
+			break
+		}
+		failure = check(snapshot)
+	}
+	close(stop)
+	<-done
+	if failure != nil {
+		t.Fatal(failure)
+	}
+
+	snapshot, err := suite.Manager.SnapshotChain()
+	if err != nil {
+		t.Fatalf("SnapshotChain() error = %v", err)
+	}
+	if snapshot.Height() != int32(len(blockLines)) {
+		t.Errorf("Height() = %d after processing all blocks, want %d", snapshot.Height(), len(blockLines))
+	}
 }
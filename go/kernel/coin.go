@@ -27,7 +27,7 @@ type Coin struct {
 
 func newCoin(ptr *C.btck_Coin, fromOwned bool) *Coin {
 	h := newHandle(unsafe.Pointer(ptr), coinCFuncs{}, fromOwned)
-	return &Coin{handle: h, coinApi: coinApi{(*C.btck_Coin)(h.ptr)}}
+	return &Coin{handle: h, coinApi: coinApi{(*C.btck_Coin)(h.ptr), h}}
 }
 
 // CoinView holds information on a transaction output, including the height it was
@@ -37,20 +37,26 @@ type CoinView struct {
 	ptr *C.btck_Coin
 }
 
-func newCoinView(ptr *C.btck_Coin) *CoinView {
+func newCoinView(ptr *C.btck_Coin, owner viewOwner) *CoinView {
 	return &CoinView{
-		coinApi: coinApi{ptr},
+		coinApi: coinApi{ptr, owner},
 		ptr:     ptr,
 	}
 }
 
 type coinApi struct {
-	ptr *C.btck_Coin
+	ptr   *C.btck_Coin
+	owner viewOwner
+}
+
+func (c *coinApi) cptr() *C.btck_Coin {
+	checkOwner(c.owner)
+	return c.ptr
 }
 
 // Copy creates a copy of the coin.
 func (c *coinApi) Copy() *Coin {
-	return newCoin(c.ptr, false)
+	return newCoin(c.cptr(), false)
 }
 
 // GetOutput returns the transaction output contained in this coin.
@@ -58,16 +64,16 @@ func (c *coinApi) Copy() *Coin {
 // The returned TransactionOutputView is a non-owned pointer valid for the
 // lifetime of this coin.
 func (c *coinApi) GetOutput() *TransactionOutputView {
-	ptr := C.btck_coin_get_output(c.ptr)
-	return newTransactionOutputView(check(ptr))
+	ptr := C.btck_coin_get_output(c.cptr())
+	return newTransactionOutputView(check(ptr), c.owner)
 }
 
 // ConfirmationHeight returns the block height where the transaction that created this coin was included in.
 func (c *coinApi) ConfirmationHeight() uint32 {
-	return uint32(C.btck_coin_confirmation_height(c.ptr))
+	return uint32(C.btck_coin_confirmation_height(c.cptr()))
 }
 
 // IsCoinbase returns true if this coin originates from a coinbase transaction.
 func (c *coinApi) IsCoinbase() bool {
-	return int(C.btck_coin_is_coinbase(c.ptr)) != 0
+	return int(C.btck_coin_is_coinbase(c.cptr())) != 0
 }
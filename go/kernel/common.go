@@ -32,12 +32,21 @@ func newUniqueHandle(ptr unsafe.Pointer, funcs destroyableFuncs) *uniqueHandle {
 		ptr:   ptr,
 		funcs: funcs,
 	}
-	runtime.SetFinalizer(h, (*uniqueHandle).destroy)
+	runtime.SetFinalizer(h, (*uniqueHandle).finalize)
+	trackHandle(ptr, funcs)
 	return h
 }
 
+func (h *uniqueHandle) finalize() {
+	if h.ptr != nil {
+		handleFinalized(h.ptr)
+	}
+	h.destroy()
+}
+
 func (h *uniqueHandle) destroy() {
 	if h.ptr != nil {
+		untrackHandle(h.ptr)
 		h.funcs.destroy(h.ptr)
 		h.ptr = nil
 	}
@@ -72,12 +81,21 @@ func newHandle(ptr unsafe.Pointer, funcs copyableFuncs, fromOwned bool) *handle
 		ptr:   ptr,
 		funcs: funcs,
 	}
-	runtime.SetFinalizer(h, (*handle).destroy)
+	runtime.SetFinalizer(h, (*handle).finalize)
+	trackHandle(ptr, funcs)
 	return h
 }
 
+func (h *handle) finalize() {
+	if h.ptr != nil {
+		handleFinalized(h.ptr)
+	}
+	h.destroy()
+}
+
 func (h *handle) destroy() {
 	if h.ptr != nil {
+		untrackHandle(h.ptr)
 		h.funcs.destroy(h.ptr)
 		h.ptr = nil
 	}
@@ -88,6 +106,30 @@ func (h *handle) Destroy() {
 	h.destroy()
 }
 
+// viewOwner is implemented by the handles of objects that views and other non-owned
+// pointers point into. Holding a viewOwner keeps the owner reachable, so it cannot be
+// finalized while the view is in use.
+type viewOwner interface {
+	alive() bool
+}
+
+func (h *uniqueHandle) alive() bool {
+	return h.ptr != nil
+}
+
+func (h *handle) alive() bool {
+	return h.ptr != nil
+}
+
+// checkOwner panics with ErrKernelUseAfterDestroy if owner was explicitly destroyed,
+// turning a use-after-free of a view into a deterministic failure. A nil owner is
+// used for pointers whose lifetime is guaranteed by the caller, e.g. in callbacks.
+func checkOwner(owner viewOwner) {
+	if owner != nil && !owner.alive() {
+		panic(ErrKernelUseAfterDestroy)
+	}
+}
+
 //export go_delete_handle
 func go_delete_handle(handle unsafe.Pointer) {
 	cgo.Handle(handle).Delete()
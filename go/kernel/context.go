@@ -5,6 +5,7 @@ package kernel
 */
 import "C"
 import (
+	"sync"
 	"unsafe"
 )
 
@@ -27,6 +28,19 @@ func (contextCFuncs) copy(ptr unsafe.Pointer) unsafe.Pointer {
 // A constructed context can be safely used from multiple threads.
 type Context struct {
 	*handle
+	callbacks *callbackQueue // shared by copies, nil if created without options
+	failure   *failureState  // shared by copies, nil if created without options
+
+	interrupts *interruptState // shared by copies
+}
+
+// interruptState tracks why a context is interrupted, so that interrupts delivered on
+// behalf of cancelled Go contexts are not reset while the kernel context should stay
+// interrupted for another reason.
+type interruptState struct {
+	mu        sync.Mutex
+	requested bool // interrupted through Context.Interrupt
+	cancelled int  // running operations that interrupted the context, see runInterruptible
 }
 
 func newContext(ptr *C.btck_Context, fromOwned bool) *Context {
@@ -42,20 +56,80 @@ func newContext(ptr *C.btck_Context, fromOwned bool) *Context {
 // Returns an error if the context cannot be created. If options is nil or not configured,
 // the context assumes mainnet chain parameters and no callbacks.
 func NewContext(options *ContextOptions) (*Context, error) {
-	ptr := C.btck_context_create((*C.btck_ContextOptions)(options.ptr))
+	var failure *failureState
+	var cOptions *C.btck_ContextOptions
+	if options != nil {
+		failure = &failureState{policy: options.errorPolicy}
+		options.registerNotifications(failure)
+		cOptions = (*C.btck_ContextOptions)(options.ptr)
+	}
+	ptr := C.btck_context_create(cOptions)
 	if ptr == nil {
-		return nil, &InternalError{"Failed to create context"}
+		return nil, &Error{Op: "create context", Code: ErrorCodeInternal}
+	}
+	ctx := newContext(ptr, true)
+	ctx.interrupts = &interruptState{}
+	if options != nil {
+		ctx.callbacks = options.callbacks
+		ctx.failure = failure
 	}
-	return newContext(ptr, true), nil
+	return ctx, nil
 }
 
 // Interrupt halts long-running validation functions like reindexing or block import.
 //
 // Returns an error if the interrupt signal cannot be delivered.
 func (ctx *Context) Interrupt() error {
+	ctx.interrupts.mu.Lock()
+	defer ctx.interrupts.mu.Unlock()
+	ctx.interrupts.requested = true
 	result := C.btck_context_interrupt((*C.btck_Context)(ctx.handle.ptr))
 	if result != 0 {
-		return &InternalError{"Context interrupt failed"}
+		return &Error{Op: "interrupt context", Code: ErrorCodeInternal}
+	}
+	return nil
+}
+
+// ResetInterrupt clears a previous Interrupt, so that long-running validation
+// functions of chainstate managers created from this context can be run again.
+//
+// Returns an error if the interrupt signal cannot be reset.
+func (ctx *Context) ResetInterrupt() error {
+	ctx.interrupts.mu.Lock()
+	defer ctx.interrupts.mu.Unlock()
+	ctx.interrupts.requested = false
+	result := C.btck_context_reset_interrupt((*C.btck_Context)(ctx.handle.ptr))
+	if result != 0 {
+		return &Error{Op: "reset context interrupt", Code: ErrorCodeInternal}
+	}
+	return nil
+}
+
+// interruptForCancellation interrupts the context on behalf of a running operation
+// whose Go context is done. finishCancellation must be called once the operation has
+// returned, unless an error is returned.
+func (ctx *Context) interruptForCancellation() error {
+	ctx.interrupts.mu.Lock()
+	defer ctx.interrupts.mu.Unlock()
+	if C.btck_context_interrupt((*C.btck_Context)(ctx.handle.ptr)) != 0 {
+		return &Error{Op: "interrupt context", Code: ErrorCodeInternal}
+	}
+	ctx.interrupts.cancelled++
+	return nil
+}
+
+// finishCancellation resets the interrupt delivered by interruptForCancellation once no
+// other interrupted operation is running, unless the context was also interrupted through
+// Interrupt or has failed, see ErrorPolicy.
+func (ctx *Context) finishCancellation() error {
+	ctx.interrupts.mu.Lock()
+	defer ctx.interrupts.mu.Unlock()
+	ctx.interrupts.cancelled--
+	if ctx.interrupts.cancelled > 0 || ctx.interrupts.requested || ctx.failure.failure() != nil {
+		return nil
+	}
+	if C.btck_context_reset_interrupt((*C.btck_Context)(ctx.handle.ptr)) != 0 {
+		return &Error{Op: "reset context interrupt", Code: ErrorCodeInternal}
 	}
 	return nil
 }
@@ -65,5 +139,45 @@ func (ctx *Context) Interrupt() error {
 // The context is reference-counted internally, so this operation is efficient and does
 // not duplicate the underlying data.
 func (ctx *Context) Copy() *Context {
-	return newContext((*C.btck_Context)(ctx.handle.ptr), false)
+	copied := newContext((*C.btck_Context)(ctx.handle.ptr), false)
+	copied.callbacks = ctx.callbacks
+	copied.failure = ctx.failure
+	copied.interrupts = ctx.interrupts
+	return copied
+}
+
+// Drain blocks until all callbacks queued for asynchronous dispatch have returned, see
+// ContextOptions.SetAsyncCallbacks. It returns immediately if callbacks are run
+// synchronously. It must not be called from within a callback.
+func (ctx *Context) Drain() {
+	if ctx.callbacks != nil {
+		ctx.callbacks.drain()
+	}
+}
+
+// PendingCallbacks returns the number of callbacks queued for asynchronous dispatch that
+// have not been started yet.
+func (ctx *Context) PendingCallbacks() int {
+	if ctx.callbacks == nil {
+		return 0
+	}
+	return ctx.callbacks.len()
+}
+
+// DroppedCallbacks returns the number of callbacks that were not run because the queue
+// for asynchronous dispatch was full, see ContextOptions.SetAsyncCallbacks.
+func (ctx *Context) DroppedCallbacks() uint64 {
+	if ctx.callbacks == nil {
+		return 0
+	}
+	return ctx.callbacks.droppedCount()
+}
+
+// Err returns the *FatalKernelError that failed the context according to its
+// ErrorPolicy, or nil if the kernel has not reported a fatal error.
+func (ctx *Context) Err() error {
+	if err := ctx.failure.failure(); err != nil {
+		return err
+	}
+	return nil
 }
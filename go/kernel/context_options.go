@@ -41,11 +41,16 @@ func (contextOptionsCFuncs) destroy(ptr unsafe.Pointer) {
 // configured, the context will be instantiated with no callbacks and for mainnet.
 type ContextOptions struct {
 	*uniqueHandle
+	callbacks     *callbackQueue
+	notifications *NotificationCallbacks // registered by NewContext, see registerNotifications
+	errorPolicy   ErrorPolicy
 }
 
 func newContextOptions(ptr *C.btck_ContextOptions) *ContextOptions {
 	h := newUniqueHandle(unsafe.Pointer(ptr), contextOptionsCFuncs{})
-	return &ContextOptions{uniqueHandle: h}
+	opts := &ContextOptions{uniqueHandle: h, callbacks: newCallbackQueue()}
+	opts.SetErrorPolicy(ErrorPolicy{})
+	return opts
 }
 
 // NewContextOptions creates an empty context options object.
@@ -69,8 +74,21 @@ func (opts *ContextOptions) SetChainParams(chainParams *ChainParameters) {
 // Parameters:
 //   - callbacks: Is set to the context options.
 func (opts *ContextOptions) SetNotifications(callbacks *NotificationCallbacks) {
+	opts.notifications = callbacks
+}
+
+// registerNotifications passes the notification callbacks to the kernel, together with
+// the failure state of the context about to be created. Every context gets its own
+// registration, as the kernel shares it with the contexts created from the options.
+func (opts *ContextOptions) registerNotifications(failure *failureState) {
+	callbacks := opts.notifications
+	if callbacks == nil {
+		// Fatal errors are still needed to apply the error policy
+		callbacks = &NotificationCallbacks{}
+	}
+	target := &notificationTarget{callbacks: callbacks, queue: opts.callbacks, failure: failure}
 	notificationCallbacks := C.btck_NotificationInterfaceCallbacks{
-		user_data:         unsafe.Pointer(cgo.NewHandle(callbacks)),
+		user_data:         unsafe.Pointer(cgo.NewHandle(target)),
 		user_data_destroy: C.btck_DestroyCallback(C.go_delete_handle),
 		block_tip:         C.btck_NotifyBlockTip(C.go_notify_block_tip_bridge),
 		header_tip:        C.btck_NotifyHeaderTip(C.go_notify_header_tip_bridge),
@@ -92,7 +110,7 @@ func (opts *ContextOptions) SetNotifications(callbacks *NotificationCallbacks) {
 //   - callbacks: The callbacks used for passing validation information to the user.
 func (opts *ContextOptions) SetValidationInterface(callbacks *ValidationInterfaceCallbacks) {
 	validationCallbacks := C.btck_ValidationInterfaceCallbacks{
-		user_data:          unsafe.Pointer(cgo.NewHandle(callbacks)),
+		user_data:          unsafe.Pointer(cgo.NewHandle(&validationTarget{callbacks: callbacks, queue: opts.callbacks})),
 		user_data_destroy:  C.btck_DestroyCallback(C.go_delete_handle),
 		block_checked:      C.btck_ValidationInterfaceBlockChecked(C.go_validation_interface_block_checked_bridge),
 		pow_valid_block:    C.btck_ValidationInterfacePoWValidBlock(C.go_validation_interface_pow_valid_block_bridge),
@@ -101,3 +119,42 @@ func (opts *ContextOptions) SetValidationInterface(callbacks *ValidationInterfac
 	}
 	C.btck_context_options_set_validation_interface((*C.btck_ContextOptions)(opts.ptr), validationCallbacks)
 }
+
+// SetAsyncCallbacks makes contexts created with the options run notification and
+// validation interface callbacks on a dedicated goroutine instead of the kernel thread
+// issuing them, so that slow callbacks do not stall validation. Callbacks are run one
+// at a time in the order they were issued, and receive the same arguments as
+// synchronous callbacks: blocks are owned references, and block tree entries remain
+// valid until the chainstate manager is destroyed.
+//
+// Call Context.Drain to wait for queued callbacks, at the latest before destroying the
+// chainstate manager. The kernel thread never waits for the queue, as it may issue
+// callbacks while holding locks that queued callbacks acquire when they call into the
+// chainstate manager. Instead, callbacks issued while the queue holds queueSize
+// callbacks are dropped and counted by Context.DroppedCallbacks. The blocks passed to
+// dropped validation interface callbacks are destroyed.
+//
+// It applies to callbacks set before and after it is called, but must be called before
+// a context is created from the options.
+//
+// Parameters:
+//   - queueSize: Maximum number of queued callbacks, further callbacks are dropped (0 for no limit)
+func (opts *ContextOptions) SetAsyncCallbacks(queueSize int) {
+	opts.callbacks.async = true
+	opts.callbacks.capacity = max(queueSize, 0)
+}
+
+// SetErrorPolicy sets how contexts created with the options react to fatal errors
+// reported by the kernel, see ErrorPolicy. Without a policy, fatal errors and flush
+// errors fail the context and are not sent anywhere.
+//
+// Parameters:
+//   - policy: Is set to the context options.
+func (opts *ContextOptions) SetErrorPolicy(policy ErrorPolicy) {
+	opts.errorPolicy = policy
+	var interruptOnFlushError C.int
+	if !policy.IgnoreFlushErrors {
+		interruptOnFlushError = 1
+	}
+	C.btck_context_options_set_interrupt_on_error((*C.btck_ContextOptions)(opts.ptr), 1, interruptOnFlushError)
+}
@@ -25,6 +25,11 @@ func TestNewContext(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:        "Nil context options",
+			setupOption: func() *ContextOptions { return nil },
+			wantErr:     false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -63,3 +68,71 @@ func TestNewContext(t *testing.T) {
 		})
 	}
 }
+
+func TestContextAsyncCallbacks(t *testing.T) {
+	blockLines := regtestBlockLines(t)
+
+	release := make(chan struct{})
+	var connected []int32
+	var checked []ValidationMode
+	node, err := OpenNode(t.TempDir(),
+		WithChain(ChainTypeRegtest),
+		WithInMemoryDBs(),
+		WithAsyncCallbacks(0),
+		WithValidationInterface(&ValidationInterfaceCallbacks{
+			OnBlockChecked: func(block *Block, state *BlockValidationState) {
+				defer block.Destroy()
+				checked = append(checked, state.ValidationMode())
+			},
+			OnBlockConnected: func(block *Block, entry *BlockTreeEntry) {
+				defer block.Destroy()
+				<-release
+				connected = append(connected, entry.Height())
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+	defer node.Close()
+
+	// Validation is not held up by the blocked callback
+	const processed = 5
+	for i := 0; i < processed; i++ {
+		block, err := NewBlock(mustDecodeHex(t, blockLines[i]))
+		if err != nil {
+			t.Fatalf("NewBlock() error = %v", err)
+		}
+		ok, _ := node.ChainstateManager().ProcessBlock(block)
+		block.Destroy()
+		if !ok {
+			t.Fatalf("ProcessBlock() failed for block %d", i+1)
+		}
+	}
+	if node.Context().PendingCallbacks() == 0 {
+		t.Error("Expected pending callbacks while a callback is blocked")
+	}
+
+	close(release)
+	node.Context().Drain()
+	if node.Context().PendingCallbacks() != 0 {
+		t.Errorf("PendingCallbacks() = %d after Drain()", node.Context().PendingCallbacks())
+	}
+
+	if len(connected) != processed {
+		t.Fatalf("Connected %d blocks, want %d", len(connected), processed)
+	}
+	for i, height := range connected {
+		if height != int32(i+1) {
+			t.Errorf("Block %d connected at height %d, want %d", i, height, i+1)
+		}
+	}
+	if len(checked) != processed {
+		t.Fatalf("Checked %d blocks, want %d", len(checked), processed)
+	}
+	for _, mode := range checked {
+		if mode != ValidationStateValid {
+			t.Errorf("Expected valid block, got validation mode %d", mode)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+package kernel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"slices"
+)
+
+// maxDecodeSize mirrors the MAX_SIZE limit the kernel applies to deserialized
+// vector lengths.
+const maxDecodeSize = 0x02000000
+
+// BlockHeader holds the fields of a block header.
+//
+// Hashes are in the internal byte order used by BlockHash.Bytes, which is the
+// reverse of their usual hex display order.
+type BlockHeader struct {
+	Version    int32
+	PrevBlock  [32]byte
+	MerkleRoot [32]byte
+	Time       uint32
+	Bits       uint32
+	Nonce      uint32
+}
+
+// Bytes returns the 80-byte consensus serialization of the header.
+func (h *BlockHeader) Bytes() []byte {
+	var buf bytes.Buffer
+	h.encode(&buf)
+	return buf.Bytes()
+}
+
+// Hash returns the block hash, the double SHA256 of the serialized header.
+func (h *BlockHeader) Hash() [32]byte {
+	return doubleSHA256(h.Bytes())
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same data as Bytes.
+func (h *BlockHeader) MarshalBinary() ([]byte, error) {
+	return h.Bytes(), nil
+}
+
+// WriteTo implements io.WriterTo, writing the serialized header to w.
+func (h *BlockHeader) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(h.Bytes())
+	return int64(n), err
+}
+
+func (h *BlockHeader) encode(buf *bytes.Buffer) {
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(h.Version)))
+	buf.Write(h.PrevBlock[:])
+	buf.Write(h.MerkleRoot[:])
+	buf.Write(binary.LittleEndian.AppendUint32(nil, h.Time))
+	buf.Write(binary.LittleEndian.AppendUint32(nil, h.Bits))
+	buf.Write(binary.LittleEndian.AppendUint32(nil, h.Nonce))
+}
+
+// DecodedBlock is a pure-Go representation of a block. Unlike Block, it holds no
+// reference to kernel memory, needs no Destroy and can be shared across goroutines.
+type DecodedBlock struct {
+	Hash         [32]byte
+	Header       BlockHeader
+	Transactions []*DecodedTransaction
+}
+
+// Bytes returns the consensus serialization of the block, including witness data.
+func (b *DecodedBlock) Bytes() []byte {
+	var buf bytes.Buffer
+	b.Header.encode(&buf)
+	writeCompactSize(&buf, uint64(len(b.Transactions)))
+	for _, tx := range b.Transactions {
+		tx.encode(&buf, true)
+	}
+	return buf.Bytes()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same data as Bytes.
+func (b *DecodedBlock) MarshalBinary() ([]byte, error) {
+	return b.Bytes(), nil
+}
+
+// WriteTo implements io.WriterTo, writing the serialized block to w.
+func (b *DecodedBlock) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(b.Bytes())
+	return int64(n), err
+}
+
+// DecodedOutPoint identifies a transaction output by the txid of its transaction
+// and its index within the transaction's outputs.
+type DecodedOutPoint struct {
+	Txid  [32]byte
+	Index uint32
+}
+
+// DecodedInput is a pure-Go representation of a transaction input.
+type DecodedInput struct {
+	PrevOut   DecodedOutPoint
+	ScriptSig []byte
+	Sequence  uint32
+	Witness   [][]byte
+}
+
+// DecodedOutput is a pure-Go representation of a transaction output.
+type DecodedOutput struct {
+	Amount       int64
+	ScriptPubkey []byte
+}
+
+// DecodedTransaction is a pure-Go representation of a transaction. Unlike
+// Transaction, it holds no reference to kernel memory, needs no Destroy and can be
+// shared across goroutines.
+//
+// Txid and Wtxid are in the internal byte order used by Txid.Bytes.
+type DecodedTransaction struct {
+	Txid     [32]byte
+	Wtxid    [32]byte
+	Version  uint32
+	Inputs   []DecodedInput
+	Outputs  []DecodedOutput
+	LockTime uint32
+}
+
+// HasWitness reports whether any input of the transaction carries witness data.
+func (tx *DecodedTransaction) HasWitness() bool {
+	for _, input := range tx.Inputs {
+		if len(input.Witness) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Bytes returns the consensus serialization of the transaction, including witness
+// data if the transaction has any.
+func (tx *DecodedTransaction) Bytes() []byte {
+	var buf bytes.Buffer
+	tx.encode(&buf, true)
+	return buf.Bytes()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same data as Bytes.
+func (tx *DecodedTransaction) MarshalBinary() ([]byte, error) {
+	return tx.Bytes(), nil
+}
+
+// WriteTo implements io.WriterTo, writing the serialized transaction to w.
+func (tx *DecodedTransaction) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(tx.Bytes())
+	return int64(n), err
+}
+
+func (tx *DecodedTransaction) encode(buf *bytes.Buffer, allowWitness bool) {
+	withWitness := allowWitness && tx.HasWitness()
+	buf.Write(binary.LittleEndian.AppendUint32(nil, tx.Version))
+	if withWitness {
+		// Marker and flag of the extended serialization format (BIP144)
+		buf.Write([]byte{0x00, 0x01})
+	}
+	writeCompactSize(buf, uint64(len(tx.Inputs)))
+	for _, input := range tx.Inputs {
+		buf.Write(input.PrevOut.Txid[:])
+		buf.Write(binary.LittleEndian.AppendUint32(nil, input.PrevOut.Index))
+		writeBytes(buf, input.ScriptSig)
+		buf.Write(binary.LittleEndian.AppendUint32(nil, input.Sequence))
+	}
+	writeCompactSize(buf, uint64(len(tx.Outputs)))
+	for _, output := range tx.Outputs {
+		buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(output.Amount)))
+		writeBytes(buf, output.ScriptPubkey)
+	}
+	if withWitness {
+		for _, input := range tx.Inputs {
+			writeCompactSize(buf, uint64(len(input.Witness)))
+			for _, item := range input.Witness {
+				writeBytes(buf, item)
+			}
+		}
+	}
+	buf.Write(binary.LittleEndian.AppendUint32(nil, tx.LockTime))
+}
+
+// Decode converts the block into a DecodedBlock, reading all of its data in a
+// single cgo call.
+//
+// Returns an error if the block cannot be serialized or decoded.
+func (b *Block) Decode() (*DecodedBlock, error) {
+	raw, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return decodeBlock(raw)
+}
+
+// Decode converts the transaction into a DecodedTransaction, reading all of its data
+// in a single cgo call.
+//
+// Returns an error if the transaction cannot be serialized or decoded.
+func (t *transactionApi) Decode() (*DecodedTransaction, error) {
+	raw, err := t.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return decodeTransaction(raw)
+}
+
+func decodeBlock(raw []byte) (*DecodedBlock, error) {
+	r := newDecodeReader(raw)
+	block := r.block()
+	if r.err == nil && r.mem.Len() != 0 {
+		r.err = &SerializationError{"Trailing data after block"}
+	}
+	if r.err != nil {
+		return nil, &Error{Op: "decode block", Code: ErrorCodeDeserialization, Err: r.err}
+	}
+	return block, nil
+}
+
+func decodeTransaction(raw []byte) (*DecodedTransaction, error) {
+	r := newDecodeReader(raw)
+	tx := r.transaction()
+	if r.err == nil && r.mem.Len() != 0 {
+		r.err = &SerializationError{"Trailing data after transaction"}
+	}
+	if r.err != nil {
+		return nil, &Error{Op: "decode transaction", Code: ErrorCodeDeserialization, Err: r.err}
+	}
+	return tx, nil
+}
+
+// decodeReader reads consensus serialized data, recording the first error
+// encountered so that callers can check it once after a sequence of reads.
+//
+// It never reads past the end of the object being decoded, so it can consume a single
+// object from a stream. The bytes consumed from a stream are kept in raw.
+type decodeReader struct {
+	mem *bytes.Reader // in-memory input, nil if reading from a stream
+	r   io.Reader     // streamed input
+	raw []byte
+	err error
+}
+
+func newDecodeReader(raw []byte) *decodeReader {
+	return &decodeReader{mem: bytes.NewReader(raw)}
+}
+
+func newStreamDecodeReader(r io.Reader) *decodeReader {
+	return &decodeReader{r: r}
+}
+
+// streamChunkSize bounds how far the buffer grows per read from a stream, so that a
+// length prefix of malformed data cannot allocate more than the stream provides.
+const streamChunkSize = 1 << 16
+
+func (r *decodeReader) read(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if r.mem == nil {
+		return r.readStream(n)
+	}
+	if n > r.mem.Len() {
+		r.err = &SerializationError{"Unexpected end of data"}
+		return nil
+	}
+	buf := make([]byte, n)
+	_, _ = io.ReadFull(r.mem, buf)
+	return buf
+}
+
+func (r *decodeReader) readStream(n int) []byte {
+	start := len(r.raw)
+	for len(r.raw)-start < n {
+		chunk := min(n-(len(r.raw)-start), streamChunkSize)
+		r.raw = slices.Grow(r.raw, chunk)
+		read, err := io.ReadFull(r.r, r.raw[len(r.raw):len(r.raw)+chunk])
+		r.raw = r.raw[:len(r.raw)+read]
+		switch {
+		case err == io.EOF && len(r.raw) == 0:
+			// The stream ended before the object started
+			r.err = io.EOF
+			return nil
+		case err == io.EOF || err == io.ErrUnexpectedEOF:
+			r.err = &SerializationError{"Unexpected end of data"}
+			return nil
+		case err != nil:
+			r.err = err
+			return nil
+		}
+	}
+	// Later reads append to raw, so the returned slice must not share its capacity
+	return r.raw[start:len(r.raw):len(r.raw)]
+}
+
+// offset returns the number of bytes consumed so far.
+func (r *decodeReader) offset() int {
+	if r.mem != nil {
+		return int(r.mem.Size()) - r.mem.Len()
+	}
+	return len(r.raw)
+}
+
+// consumedSince returns the bytes consumed since offset start.
+func (r *decodeReader) consumedSince(start int) []byte {
+	if r.mem == nil {
+		return r.raw[start:]
+	}
+	raw := make([]byte, r.offset()-start)
+	_, _ = r.mem.ReadAt(raw, int64(start))
+	return raw
+}
+
+func (r *decodeReader) uint8() uint8 {
+	if b := r.read(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *decodeReader) uint16() uint16 {
+	if b := r.read(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *decodeReader) uint32() uint32 {
+	if b := r.read(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *decodeReader) uint64() uint64 {
+	if b := r.read(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *decodeReader) hash() (h [32]byte) {
+	copy(h[:], r.read(32))
+	return
+}
+
+func (r *decodeReader) compactSize() uint64 {
+	var size uint64
+	var minSize uint64
+	switch prefix := r.uint8(); prefix {
+	case 0xfd:
+		size, minSize = uint64(r.uint16()), 0xfd
+	case 0xfe:
+		size, minSize = uint64(r.uint32()), 0x10000
+	case 0xff:
+		size, minSize = r.uint64(), 0x100000000
+	default:
+		return uint64(prefix)
+	}
+	if r.err == nil && size < minSize {
+		r.err = &SerializationError{"Non-canonical compact size"}
+	}
+	if r.err == nil && size > maxDecodeSize {
+		r.err = &SerializationError{"Compact size exceeds maximum size"}
+	}
+	return size
+}
+
+func (r *decodeReader) bytes() []byte {
+	size := r.compactSize()
+	if r.err != nil {
+		return nil
+	}
+	return r.read(int(size))
+}
+
+func (r *decodeReader) block() *DecodedBlock {
+	block := &DecodedBlock{}
+	block.Header = r.header()
+	txCount := r.compactSize()
+	for i := uint64(0); i < txCount && r.err == nil; i++ {
+		block.Transactions = append(block.Transactions, r.transaction())
+	}
+	if r.err != nil {
+		return nil
+	}
+	block.Hash = block.Header.Hash()
+	return block
+}
+
+func (r *decodeReader) header() BlockHeader {
+	return BlockHeader{
+		Version:    int32(r.uint32()),
+		PrevBlock:  r.hash(),
+		MerkleRoot: r.hash(),
+		Time:       r.uint32(),
+		Bits:       r.uint32(),
+		Nonce:      r.uint32(),
+	}
+}
+
+func (r *decodeReader) inputs() []DecodedInput {
+	count := r.compactSize()
+	var inputs []DecodedInput
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		inputs = append(inputs, DecodedInput{
+			PrevOut:   DecodedOutPoint{Txid: r.hash(), Index: r.uint32()},
+			ScriptSig: r.bytes(),
+			Sequence:  r.uint32(),
+		})
+	}
+	return inputs
+}
+
+func (r *decodeReader) outputs() []DecodedOutput {
+	count := r.compactSize()
+	var outputs []DecodedOutput
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		outputs = append(outputs, DecodedOutput{
+			Amount:       int64(r.uint64()),
+			ScriptPubkey: r.bytes(),
+		})
+	}
+	return outputs
+}
+
+// transaction decodes a transaction following the rules of UnserializeTransaction,
+// accepting both the legacy and the extended (witness) serialization format.
+func (r *decodeReader) transaction() *DecodedTransaction {
+	start := r.offset()
+
+	tx := &DecodedTransaction{}
+	tx.Version = r.uint32()
+	var flags uint8
+	tx.Inputs = r.inputs()
+	if len(tx.Inputs) == 0 {
+		flags = r.uint8()
+		if flags != 0 {
+			tx.Inputs = r.inputs()
+			tx.Outputs = r.outputs()
+		}
+	} else {
+		tx.Outputs = r.outputs()
+	}
+	if flags&1 != 0 {
+		flags ^= 1
+		for i := range tx.Inputs {
+			count := r.compactSize()
+			for j := uint64(0); j < count && r.err == nil; j++ {
+				tx.Inputs[i].Witness = append(tx.Inputs[i].Witness, r.bytes())
+			}
+		}
+		if r.err == nil && !tx.HasWitness() {
+			r.err = &SerializationError{"Superfluous witness record"}
+		}
+	}
+	if r.err == nil && flags != 0 {
+		r.err = &SerializationError{"Unknown transaction optional data"}
+	}
+	tx.LockTime = r.uint32()
+	if r.err != nil {
+		return nil
+	}
+
+	tx.Wtxid = doubleSHA256(r.consumedSince(start))
+	if tx.HasWitness() {
+		var buf bytes.Buffer
+		tx.encode(&buf, false)
+		tx.Txid = doubleSHA256(buf.Bytes())
+	} else {
+		tx.Txid = tx.Wtxid
+	}
+	return tx
+}
+
+func writeCompactSize(buf *bytes.Buffer, size uint64) {
+	switch {
+	case size < 0xfd:
+		buf.WriteByte(byte(size))
+	case size <= 0xffff:
+		buf.WriteByte(0xfd)
+		buf.Write(binary.LittleEndian.AppendUint16(nil, uint16(size)))
+	case size <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(size)))
+	default:
+		buf.WriteByte(0xff)
+		buf.Write(binary.LittleEndian.AppendUint64(nil, size))
+	}
+}
+
+func writeBytes(buf *bytes.Buffer, data []byte) {
+	writeCompactSize(buf, uint64(len(data)))
+	buf.Write(data)
+}
+
+func doubleSHA256(data []byte) [32]byte {
+	first := sha256.Sum256(data)
+	return sha256.Sum256(first[:])
+}
@@ -0,0 +1,138 @@
+package kernel
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestBlockDecode(t *testing.T) {
+	genesisHex := "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c0101000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+	genesisBytes := mustDecodeHex(t, genesisHex)
+
+	block, err := NewBlock(genesisBytes)
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	decoded, err := block.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	wantHeader := BlockHeader{Version: 1, Time: 1231006505, Bits: 0x1d00ffff, Nonce: 2083236893}
+	copy(wantHeader.MerkleRoot[:], reverseBytes(mustDecodeHex(t, "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")))
+	if decoded.Header != wantHeader {
+		t.Errorf("Header = %+v, want %+v", decoded.Header, wantHeader)
+	}
+	if decoded.Hash != block.Hash().Bytes() {
+		t.Errorf("Hash = %x, want %x", decoded.Hash, block.Hash().Bytes())
+	}
+	if !bytes.Equal(decoded.Bytes(), genesisBytes) {
+		t.Errorf("Bytes() does not round-trip the genesis block")
+	}
+
+	if len(decoded.Transactions) != 1 {
+		t.Fatalf("Expected 1 transaction, got %d", len(decoded.Transactions))
+	}
+	coinbase := decoded.Transactions[0]
+	if hex.EncodeToString(reverseBytes(coinbase.Txid[:])) != "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b" {
+		t.Errorf("Coinbase txid = %x", reverseBytes(coinbase.Txid[:]))
+	}
+	if coinbase.Wtxid != coinbase.Txid {
+		t.Errorf("Wtxid of a transaction without witness should equal its txid")
+	}
+	if len(coinbase.Inputs) != 1 || coinbase.Inputs[0].PrevOut.Index != 0xffffffff || coinbase.Inputs[0].Sequence != 0xffffffff {
+		t.Errorf("Unexpected coinbase inputs %+v", coinbase.Inputs)
+	}
+	if len(coinbase.Outputs) != 1 || coinbase.Outputs[0].Amount != 5000000000 || len(coinbase.Outputs[0].ScriptPubkey) != 67 {
+		t.Errorf("Unexpected coinbase outputs %+v", coinbase.Outputs)
+	}
+}
+
+func TestTransactionDecode(t *testing.T) {
+	txBytes := mustDecodeHex(t, coinbaseTxHex)
+
+	tx, err := NewTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	decoded, err := tx.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	assertDecodedTransaction(t, decoded, &tx.transactionApi)
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawHex string
+	}{
+		{"truncated header", "01000000"},
+		{"non-canonical compact size", "01000000" + "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" + "00000000" + "00000000" + "00000000" + "fd0100"},
+		{"trailing data", "01000000" + "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" + "00000000" + "00000000" + "00000000" + "00" + "00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeBlock(mustDecodeHex(t, tt.rawHex))
+			var serializationErr *SerializationError
+			if !errors.As(err, &serializationErr) {
+				t.Errorf("decodeBlock() error = %v, want *SerializationError", err)
+			}
+			if !errors.Is(err, ErrDeserialization) {
+				t.Errorf("decodeBlock() error = %v, want %v", err, ErrDeserialization)
+			}
+		})
+	}
+}
+
+// assertDecodedTransaction cross-checks a decoded transaction against the kernel accessors
+func assertDecodedTransaction(t *testing.T, decoded *DecodedTransaction, tx *transactionApi) {
+	t.Helper()
+
+	if decoded.Txid != tx.GetTxid().Bytes() {
+		t.Errorf("Txid = %x, want %x", decoded.Txid, tx.GetTxid().Bytes())
+	}
+
+	raw, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(decoded.Bytes(), raw) {
+		t.Errorf("Bytes() = %x, want %x", decoded.Bytes(), raw)
+	}
+	if decoded.Wtxid != doubleSHA256(raw) {
+		t.Errorf("Wtxid = %x, want %x", decoded.Wtxid, doubleSHA256(raw))
+	}
+
+	if uint64(len(decoded.Inputs)) != tx.CountInputs() {
+		t.Fatalf("Decoded %d inputs, want %d", len(decoded.Inputs), tx.CountInputs())
+	}
+	for i, input := range tx.Inputs() {
+		outPoint := input.GetOutPoint()
+		if decoded.Inputs[i].PrevOut.Index != outPoint.GetIndex() || decoded.Inputs[i].PrevOut.Txid != outPoint.GetTxid().Bytes() {
+			t.Errorf("Input %d out point = %+v, want %x:%d", i, decoded.Inputs[i].PrevOut, outPoint.GetTxid().Bytes(), outPoint.GetIndex())
+		}
+	}
+
+	if uint64(len(decoded.Outputs)) != tx.CountOutputs() {
+		t.Fatalf("Decoded %d outputs, want %d", len(decoded.Outputs), tx.CountOutputs())
+	}
+	for i, output := range tx.Outputs() {
+		if decoded.Outputs[i].Amount != output.Amount() {
+			t.Errorf("Output %d amount = %d, want %d", i, decoded.Outputs[i].Amount, output.Amount())
+		}
+		script, err := output.ScriptPubkey().Bytes()
+		if err != nil {
+			t.Fatalf("ScriptPubkey().Bytes() error = %v", err)
+		}
+		if !bytes.Equal(decoded.Outputs[i].ScriptPubkey, script) {
+			t.Errorf("Output %d script pubkey = %x, want %x", i, decoded.Outputs[i].ScriptPubkey, script)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package kernel
+
+/*
+#include "kernel/bitcoinkernel.h"
+*/
+import "C"
+
+// Deployment identifies a consensus rule change deployment.
+//
+// DeploymentHeightInCB, DeploymentDERSig, DeploymentCLTV, DeploymentCSV and
+// DeploymentSegwit are buried deployments enforced from a fixed height. The
+// remaining deployments are activated through BIP9 versionbits signalling.
+type Deployment C.btck_Deployment
+
+const (
+	DeploymentHeightInCB Deployment = C.btck_Deployment_HEIGHTINCB // BIP34, block height in coinbase
+	DeploymentDERSig     Deployment = C.btck_Deployment_DERSIG     // BIP66, strict DER signatures
+	DeploymentCLTV       Deployment = C.btck_Deployment_CLTV       // BIP65, CHECKLOCKTIMEVERIFY
+	DeploymentCSV        Deployment = C.btck_Deployment_CSV        // BIPs 68, 112 & 113, relative lock-times
+	DeploymentSegwit     Deployment = C.btck_Deployment_SEGWIT     // BIPs 141, 143 & 147, segregated witness
+	DeploymentTestDummy  Deployment = C.btck_Deployment_TESTDUMMY  // Dummy versionbits deployment used for testing
+	DeploymentTaproot    Deployment = C.btck_Deployment_TAPROOT    // BIPs 340-342, schnorr signatures and taproot
+)
+
+// Deployments lists all known deployments in the order used by getdeploymentinfo.
+var Deployments = []Deployment{
+	DeploymentHeightInCB,
+	DeploymentDERSig,
+	DeploymentCLTV,
+	DeploymentCSV,
+	DeploymentSegwit,
+	DeploymentTestDummy,
+	DeploymentTaproot,
+}
+
+func (d Deployment) c() C.btck_Deployment {
+	switch d {
+	case DeploymentHeightInCB, DeploymentDERSig, DeploymentCLTV, DeploymentCSV, DeploymentSegwit, DeploymentTestDummy, DeploymentTaproot:
+		return C.btck_Deployment(d)
+	default:
+		panic("Invalid deployment")
+	}
+}
+
+// String returns the deployment name as reported by getdeploymentinfo.
+func (d Deployment) String() string {
+	switch d {
+	case DeploymentHeightInCB:
+		return "bip34"
+	case DeploymentDERSig:
+		return "bip66"
+	case DeploymentCLTV:
+		return "bip65"
+	case DeploymentCSV:
+		return "csv"
+	case DeploymentSegwit:
+		return "segwit"
+	case DeploymentTestDummy:
+		return "testdummy"
+	case DeploymentTaproot:
+		return "taproot"
+	default:
+		return "unknown"
+	}
+}
+
+// DeploymentState is the BIP9 state of a versionbits deployment.
+type DeploymentState C.btck_DeploymentState
+
+const (
+	DeploymentStateDefined  DeploymentState = C.btck_DeploymentState_DEFINED   // First state that each softfork starts out as
+	DeploymentStateStarted  DeploymentState = C.btck_DeploymentState_STARTED   // Signalling for the deployment has started
+	DeploymentStateLockedIn DeploymentState = C.btck_DeploymentState_LOCKED_IN // The signalling threshold was reached in the previous period
+	DeploymentStateActive   DeploymentState = C.btck_DeploymentState_ACTIVE    // The deployment is enforced, final state
+	DeploymentStateFailed   DeploymentState = C.btck_DeploymentState_FAILED    // The deployment timed out without activating, final state
+)
+
+// String returns the state name as reported by getdeploymentinfo.
+func (s DeploymentState) String() string {
+	switch s {
+	case DeploymentStateDefined:
+		return "defined"
+	case DeploymentStateStarted:
+		return "started"
+	case DeploymentStateLockedIn:
+		return "locked_in"
+	case DeploymentStateActive:
+		return "active"
+	case DeploymentStateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// DeploymentInfo describes the state of a deployment at a block, mirroring the
+// getdeploymentinfo RPC.
+type DeploymentInfo struct {
+	Deployment Deployment
+	Buried     bool      // Whether this is a buried deployment
+	Active     bool      // Whether the rules are enforced for the block following the queried block
+	Height     int32     // Height from which the rules are enforced, or -1 if not known yet
+	BIP9       *BIP9Info // Versionbits details, nil for buried deployments
+}
+
+// BIP9Info holds the versionbits parameters and state of a deployment.
+type BIP9Info struct {
+	Bit                 int             // The version bit used for signalling
+	StartTime           int64           // Median time past from which signalling starts
+	Timeout             int64           // Median time past at which the deployment fails if not locked in
+	MinActivationHeight int32           // Minimum height at which the deployment can become active
+	State               DeploymentState // State for the queried block
+	NextState           DeploymentState // State for the block following the queried block
+	Since               int32           // Height of the first block to which State applies
+	Statistics          *BIP9Statistics // Signalling statistics of the current period, nil if signalling is not applicable
+}
+
+// BIP9Statistics holds the signalling statistics of the current period.
+type BIP9Statistics struct {
+	Period    uint32 // Length of the signalling period in blocks
+	Threshold uint32 // Number of signalling blocks required for locking in, 0 if already locked in
+	Elapsed   uint32 // Number of blocks elapsed since the beginning of the current period
+	Count     uint32 // Number of signalling blocks since the beginning of the current period
+	Possible  bool   // Whether the threshold can still be reached in the current period
+}
+
+func newDeploymentInfo(deployment Deployment, info *C.btck_DeploymentInfo) *DeploymentInfo {
+	result := &DeploymentInfo{
+		Deployment: deployment,
+		Buried:     info.buried != 0,
+		Active:     info.active != 0,
+		Height:     int32(info.height),
+	}
+	if result.Buried {
+		return result
+	}
+	result.BIP9 = &BIP9Info{
+		Bit:                 int(info.bit),
+		StartTime:           int64(info.start_time),
+		Timeout:             int64(info.timeout),
+		MinActivationHeight: int32(info.min_activation_height),
+		State:               DeploymentState(info.state),
+		NextState:           DeploymentState(info.state_next),
+		Since:               int32(info.since),
+	}
+	if info.has_statistics != 0 {
+		result.BIP9.Statistics = &BIP9Statistics{
+			Period:    uint32(info.period),
+			Threshold: uint32(info.threshold),
+			Elapsed:   uint32(info.elapsed),
+			Count:     uint32(info.count),
+			Possible:  info.possible != 0,
+		}
+	}
+	return result
+}
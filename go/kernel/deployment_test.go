@@ -0,0 +1,16 @@
+package kernel
+
+import "testing"
+
+func TestDeploymentString(t *testing.T) {
+	want := []string{"bip34", "bip66", "bip65", "csv", "segwit", "testdummy", "taproot"}
+	for i, deployment := range Deployments {
+		if deployment.String() != want[i] {
+			t.Errorf("Deployment(%d).String() = %q, want %q", deployment, deployment.String(), want[i])
+		}
+	}
+
+	if DeploymentStateLockedIn.String() != "locked_in" {
+		t.Errorf("DeploymentStateLockedIn.String() = %q, want %q", DeploymentStateLockedIn.String(), "locked_in")
+	}
+}
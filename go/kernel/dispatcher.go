@@ -0,0 +1,211 @@
+package kernel
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// Dispatcher fans kernel notifications and validation interface callbacks out to any
+// number of subscribers. Unlike the callbacks passed to ContextOptions directly,
+// subscribers can be added and removed at any time while the context is in use.
+//
+// Subscribers are called sequentially in subscription order on the thread issuing
+// the callback. A panic raised by one subscriber is recovered and reported to the
+// panic handler, and does not prevent the remaining subscribers from being called.
+// Without a panic handler, it is handled like a panic of any other kernel callback,
+// see SetCallbackPanicHandler.
+//
+// Each subscriber receives its own owned reference to blocks passed to validation
+// interface callbacks, so one subscriber destroying its block does not affect others.
+type Dispatcher struct {
+	onPanic func(err *SubscriberPanicError)
+
+	mu          sync.RWMutex
+	subscribers []*Subscription
+}
+
+// Subscription represents the registration of a subscriber with a Dispatcher.
+type Subscription struct {
+	name          string
+	dispatcher    *Dispatcher
+	notifications *NotificationCallbacks
+	validation    *ValidationInterfaceCallbacks
+}
+
+// NewDispatcher creates a dispatcher without subscribers. Register it with a context
+// through Attach.
+//
+// Parameters:
+//   - onPanic: Called with the recovered value when a subscriber panics (can be nil to use the CallbackPanicHandler)
+func NewDispatcher(onPanic func(err *SubscriberPanicError)) *Dispatcher {
+	return &Dispatcher{onPanic: onPanic}
+}
+
+// Attach registers the dispatcher as the notification and validation interface
+// callbacks of the context options. Contexts created from the options deliver their
+// callbacks to the subscribers of the dispatcher.
+//
+// Parameters:
+//   - opts: Context options to register the dispatcher with
+func (d *Dispatcher) Attach(opts *ContextOptions) {
+	opts.SetNotifications(d.NotificationCallbacks())
+	opts.SetValidationInterface(d.ValidationInterfaceCallbacks())
+}
+
+// Subscribe adds a subscriber. It receives all callbacks issued after Subscribe returns.
+//
+// Parameters:
+//   - name: Identifies the subscriber in SubscriberPanicError
+//   - notifications: Notification callbacks of the subscriber (can be nil)
+//   - validation: Validation interface callbacks of the subscriber (can be nil)
+func (d *Dispatcher) Subscribe(name string, notifications *NotificationCallbacks, validation *ValidationInterfaceCallbacks) *Subscription {
+	sub := &Subscription{
+		name:          name,
+		dispatcher:    d,
+		notifications: notifications,
+		validation:    validation,
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Copy on write, so that callbacks in flight keep iterating over their snapshot
+	subscribers := make([]*Subscription, len(d.subscribers), len(d.subscribers)+1)
+	copy(subscribers, d.subscribers)
+	d.subscribers = append(subscribers, sub)
+	return sub
+}
+
+// Len returns the number of current subscribers.
+func (d *Dispatcher) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.subscribers)
+}
+
+// Name returns the name the subscription was created with.
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Unsubscribe removes the subscriber from its dispatcher. It may be called from
+// within a callback, and calling it more than once has no effect.
+//
+// A callback that is being dispatched concurrently may still reach the subscriber
+// after Unsubscribe returns.
+func (s *Subscription) Unsubscribe() {
+	d := s.dispatcher
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	subscribers := make([]*Subscription, 0, len(d.subscribers))
+	for _, sub := range d.subscribers {
+		if sub != s {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	d.subscribers = subscribers
+}
+
+// NotificationCallbacks returns notification callbacks that forward to all subscribers.
+func (d *Dispatcher) NotificationCallbacks() *NotificationCallbacks {
+	return &NotificationCallbacks{
+		OnBlockTip: func(state SynchronizationState, entry *BlockTreeEntry, progress float64) {
+			d.notify("OnBlockTip", func(cb *NotificationCallbacks) bool { return cb.OnBlockTip != nil }, func(cb *NotificationCallbacks) {
+				cb.OnBlockTip(state, entry, progress)
+			})
+		},
+		OnHeaderTip: func(state SynchronizationState, height int64, timestamp int64, presync bool) {
+			d.notify("OnHeaderTip", func(cb *NotificationCallbacks) bool { return cb.OnHeaderTip != nil }, func(cb *NotificationCallbacks) {
+				cb.OnHeaderTip(state, height, timestamp, presync)
+			})
+		},
+		OnProgress: func(title string, percent int, resumable bool) {
+			d.notify("OnProgress", func(cb *NotificationCallbacks) bool { return cb.OnProgress != nil }, func(cb *NotificationCallbacks) {
+				cb.OnProgress(title, percent, resumable)
+			})
+		},
+		OnWarningSet: func(warning Warning, message string) {
+			d.notify("OnWarningSet", func(cb *NotificationCallbacks) bool { return cb.OnWarningSet != nil }, func(cb *NotificationCallbacks) {
+				cb.OnWarningSet(warning, message)
+			})
+		},
+		OnWarningUnset: func(warning Warning) {
+			d.notify("OnWarningUnset", func(cb *NotificationCallbacks) bool { return cb.OnWarningUnset != nil }, func(cb *NotificationCallbacks) {
+				cb.OnWarningUnset(warning)
+			})
+		},
+		OnFlushError: func(message string) {
+			d.notify("OnFlushError", func(cb *NotificationCallbacks) bool { return cb.OnFlushError != nil }, func(cb *NotificationCallbacks) {
+				cb.OnFlushError(message)
+			})
+		},
+		OnFatalError: func(message string) {
+			d.notify("OnFatalError", func(cb *NotificationCallbacks) bool { return cb.OnFatalError != nil }, func(cb *NotificationCallbacks) {
+				cb.OnFatalError(message)
+			})
+		},
+	}
+}
+
+// ValidationInterfaceCallbacks returns validation interface callbacks that forward to
+// all subscribers.
+func (d *Dispatcher) ValidationInterfaceCallbacks() *ValidationInterfaceCallbacks {
+	return &ValidationInterfaceCallbacks{
+		OnBlockChecked: func(block *Block, state *BlockValidationState) {
+			d.validate("OnBlockChecked", block, func(cb *ValidationInterfaceCallbacks) bool { return cb.OnBlockChecked != nil }, func(cb *ValidationInterfaceCallbacks, block *Block) {
+				cb.OnBlockChecked(block, state)
+			})
+		},
+		OnPoWValidBlock: func(block *Block, entry *BlockTreeEntry) {
+			d.validate("OnPoWValidBlock", block, func(cb *ValidationInterfaceCallbacks) bool { return cb.OnPoWValidBlock != nil }, func(cb *ValidationInterfaceCallbacks, block *Block) {
+				cb.OnPoWValidBlock(block, entry)
+			})
+		},
+		OnBlockConnected: func(block *Block, entry *BlockTreeEntry) {
+			d.validate("OnBlockConnected", block, func(cb *ValidationInterfaceCallbacks) bool { return cb.OnBlockConnected != nil }, func(cb *ValidationInterfaceCallbacks, block *Block) {
+				cb.OnBlockConnected(block, entry)
+			})
+		},
+		OnBlockDisconnected: func(block *Block, entry *BlockTreeEntry) {
+			d.validate("OnBlockDisconnected", block, func(cb *ValidationInterfaceCallbacks) bool { return cb.OnBlockDisconnected != nil }, func(cb *ValidationInterfaceCallbacks, block *Block) {
+				cb.OnBlockDisconnected(block, entry)
+			})
+		},
+	}
+}
+
+func (d *Dispatcher) snapshot() []*Subscription {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.subscribers
+}
+
+func (d *Dispatcher) notify(callback string, has func(*NotificationCallbacks) bool, call func(*NotificationCallbacks)) {
+	for _, sub := range d.snapshot() {
+		if sub.notifications != nil && has(sub.notifications) {
+			d.invoke(sub, callback, func() { call(sub.notifications) })
+		}
+	}
+}
+
+func (d *Dispatcher) validate(callback string, block *Block, has func(*ValidationInterfaceCallbacks) bool, call func(*ValidationInterfaceCallbacks, *Block)) {
+	defer block.Destroy()
+	for _, sub := range d.snapshot() {
+		if sub.validation != nil && has(sub.validation) {
+			d.invoke(sub, callback, func() { call(sub.validation, block.Copy()) })
+		}
+	}
+}
+
+func (d *Dispatcher) invoke(sub *Subscription, callback string, fn func()) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if d.onPanic != nil {
+			d.onPanic(&SubscriberPanicError{Subscriber: sub.name, Value: r, Stack: debug.Stack()})
+			return
+		}
+		handleCallbackPanic(&CallbackPanicError{Callback: callback, Value: r, Stack: debug.Stack()})
+	}()
+	fn()
+}
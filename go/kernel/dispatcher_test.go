@@ -0,0 +1,125 @@
+package kernel
+
+import (
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	var panics []*SubscriberPanicError
+	dispatcher := NewDispatcher(func(err *SubscriberPanicError) {
+		panics = append(panics, err)
+	})
+
+	var indexerHeights, metricsHeights []int32
+	indexer := dispatcher.Subscribe("indexer", nil, &ValidationInterfaceCallbacks{
+		OnBlockConnected: func(block *Block, entry *BlockTreeEntry) {
+			indexerHeights = append(indexerHeights, entry.Height())
+			// Destroying its own block must not affect other subscribers
+			block.Destroy()
+		},
+	})
+	var metrics *Subscription
+	metrics = dispatcher.Subscribe("metrics", nil, &ValidationInterfaceCallbacks{
+		OnBlockConnected: func(block *Block, entry *BlockTreeEntry) {
+			if _, err := block.Bytes(); err != nil {
+				t.Errorf("Bytes() error = %v", err)
+			}
+			metricsHeights = append(metricsHeights, entry.Height())
+			if entry.Height() == 2 {
+				metrics.Unsubscribe()
+			}
+		},
+	})
+	dispatcher.Subscribe("faulty", &NotificationCallbacks{
+		OnBlockTip: func(state SynchronizationState, entry *BlockTreeEntry, progress float64) {
+			panic("tip handler failed")
+		},
+	}, nil)
+
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 4,
+		NotificationCallbacks:  dispatcher.NotificationCallbacks(),
+		ValidationCallbacks:    dispatcher.ValidationInterfaceCallbacks(),
+	}
+	suite.Setup(t)
+
+	if len(indexerHeights) != 4 {
+		t.Errorf("Indexer received heights %v, want [1 2 3 4]", indexerHeights)
+	}
+	if len(metricsHeights) != 2 {
+		t.Errorf("Metrics received heights %v, want [1 2]", metricsHeights)
+	}
+	if len(panics) == 0 {
+		t.Fatal("Expected panics of the faulty subscriber to be recovered")
+	}
+	if panics[0].Subscriber != "faulty" || panics[0].Value != "tip handler failed" {
+		t.Errorf("Unexpected panic error %v", panics[0])
+	}
+
+	indexer.Unsubscribe()
+	if dispatcher.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", dispatcher.Len())
+	}
+}
+
+func TestDispatcherNotifications(t *testing.T) {
+	var recovered []string
+	dispatcher := NewDispatcher(func(err *SubscriberPanicError) {
+		recovered = append(recovered, err.Subscriber)
+		if len(err.Stack) == 0 {
+			t.Error("Expected stack trace in SubscriberPanicError")
+		}
+	})
+	callbacks := dispatcher.NotificationCallbacks()
+
+	var order []string
+	subscriber := func(name string, fail bool) *Subscription {
+		return dispatcher.Subscribe(name, &NotificationCallbacks{
+			OnProgress: func(title string, percent int, resumable bool) {
+				order = append(order, name)
+				if fail {
+					panic(name)
+				}
+			},
+		}, nil)
+	}
+	first := subscriber("first", true)
+	subscriber("second", false)
+	// Subscribers without the callback set are skipped
+	dispatcher.Subscribe("validation only", nil, &ValidationInterfaceCallbacks{})
+
+	callbacks.OnProgress("Verifying blocks", 10, false)
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("Call order = %v, want [first second]", order)
+	}
+	if len(recovered) != 1 || recovered[0] != "first" {
+		t.Errorf("Recovered panics from %v, want [first]", recovered)
+	}
+
+	first.Unsubscribe()
+	first.Unsubscribe()
+	order = nil
+	callbacks.OnProgress("Verifying blocks", 20, false)
+	if len(order) != 1 || order[0] != "second" {
+		t.Errorf("Call order after Unsubscribe = %v, want [second]", order)
+	}
+
+	// Without a handler, panics are passed to the callback panic handler
+	var handled []*CallbackPanicError
+	SetCallbackPanicHandler(func(err *CallbackPanicError) { handled = append(handled, err) })
+	t.Cleanup(func() {
+		SetCallbackPanicHandler(nil)
+		ClearCallbackPanic()
+	})
+	fallback := NewDispatcher(nil)
+	fallback.Subscribe("faulty", &NotificationCallbacks{
+		OnFatalError: func(message string) { panic(message) },
+	}, nil)
+	fallback.NotificationCallbacks().OnFatalError("fatal")
+	if len(handled) != 1 || handled[0].Callback != "OnFatalError" || handled[0].Value != "fatal" {
+		t.Errorf("Callback panic handler received %v, want panic of OnFatalError", handled)
+	}
+	if last := LastCallbackPanic(); last == nil || last.Callback != "OnFatalError" {
+		t.Errorf("LastCallbackPanic() = %v, want panic of OnFatalError", last)
+	}
+}
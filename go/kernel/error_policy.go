@@ -0,0 +1,67 @@
+package kernel
+
+import (
+	"sync"
+)
+
+// ErrorPolicy configures how a context reacts to fatal errors reported by the kernel,
+// see ContextOptions.SetErrorPolicy.
+//
+// A fatal error fails the context: it is interrupted, so that running imports return,
+// and ChainstateManager methods that validate blocks or read block data return an Error
+// with code ErrorCodeFatal wrapping a *FatalKernelError from then on. The failure cannot
+// be reset; the chainstate manager and context should be destroyed and recreated, e.g.
+// by restarting the service. NotificationCallbacks.OnFatalError and OnFlushError are
+// still called in addition.
+type ErrorPolicy struct {
+	// IgnoreFlushErrors only reports failures to flush the chainstate to disk to
+	// NotificationCallbacks.OnFlushError instead of failing the context.
+	IgnoreFlushErrors bool
+
+	// Errors receives the *FatalKernelError that failed the context (can be nil). The
+	// send never blocks the kernel thread, so the error is dropped if the channel is
+	// not ready to receive it; use a buffered channel.
+	Errors chan<- error
+}
+
+// failureState records the first fatal error reported to a context. It is shared by
+// the copies of the context and the chainstate managers created from it. The context
+// itself is interrupted by the kernel, see ContextOptions.SetErrorPolicy.
+type failureState struct {
+	policy ErrorPolicy
+
+	mu  sync.Mutex
+	err *FatalKernelError
+}
+
+// fail records err unless the policy ignores it or the context failed before, and
+// sends it to the error channel of the policy.
+func (f *failureState) fail(err *FatalKernelError) {
+	if err.Flush && f.policy.IgnoreFlushErrors {
+		return
+	}
+	f.mu.Lock()
+	if f.err != nil {
+		f.mu.Unlock()
+		return
+	}
+	f.err = err
+	f.mu.Unlock()
+
+	if f.policy.Errors != nil {
+		select {
+		case f.policy.Errors <- err:
+		default:
+		}
+	}
+}
+
+// failure returns the error that failed the context, or nil.
+func (f *failureState) failure() *FatalKernelError {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
@@ -0,0 +1,98 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFailureState(t *testing.T) {
+	errs := make(chan error, 1)
+	failure := &failureState{policy: ErrorPolicy{IgnoreFlushErrors: true, Errors: errs}}
+
+	failure.fail(&FatalKernelError{Msg: "flush", Flush: true})
+	if err := failure.failure(); err != nil {
+		t.Fatalf("failure() = %v after ignored flush error, want nil", err)
+	}
+
+	first := &FatalKernelError{Msg: "first"}
+	failure.fail(first)
+	failure.fail(&FatalKernelError{Msg: "second"})
+	if err := failure.failure(); err != first {
+		t.Errorf("failure() = %v, want the first fatal error", err)
+	}
+
+	select {
+	case err := <-errs:
+		if err != first {
+			t.Errorf("Errors received %v, want the first fatal error", err)
+		}
+	default:
+		t.Fatal("Expected the fatal error to be sent to the Errors channel")
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected a single error to be sent, %d more pending", len(errs))
+	}
+
+	var unset *failureState
+	if unset.failure() != nil {
+		t.Error("Expected a nil failure state to report no failure")
+	}
+}
+
+func TestErrorPolicy(t *testing.T) {
+	blockLines := regtestBlockLines(t)
+	errs := make(chan error, 1)
+
+	node, err := OpenNode(t.TempDir(), WithChain(ChainTypeRegtest), WithInMemoryDBs(), WithErrorPolicy(ErrorPolicy{Errors: errs}))
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+	defer node.Close()
+	manager := node.ChainstateManager()
+
+	block, err := NewBlock(mustDecodeHex(t, blockLines[0]))
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	defer block.Destroy()
+	if ok, _ := manager.ProcessBlock(block); !ok {
+		t.Fatal("ProcessBlock() failed before the fatal error")
+	}
+	if err := manager.Err(); err != nil {
+		t.Fatalf("Err() = %v before the fatal error", err)
+	}
+
+	// Deliver a flush error like the notification bridge does
+	node.Context().failure.fail(&FatalKernelError{Msg: "disk full", Flush: true})
+
+	var fatal *FatalKernelError
+	if err := <-errs; !errors.As(err, &fatal) || fatal.Msg != "disk full" || !fatal.Flush {
+		t.Errorf("Errors received %v, want the flush error", err)
+	}
+	if err := manager.Err(); !errors.As(err, &fatal) {
+		t.Errorf("Err() = %v, want *FatalKernelError", err)
+	}
+	if err := node.Context().Err(); err == nil {
+		t.Error("Context().Err() = nil after the fatal error")
+	}
+
+	if ok, _ := manager.ProcessBlock(block); ok {
+		t.Error("ProcessBlock() succeeded after the fatal error")
+	}
+	if _, _, err := manager.ProcessBlockContext(context.Background(), block); !errors.Is(err, ErrFatal) {
+		t.Errorf("ProcessBlockContext() error = %v, want ErrFatal", err)
+	}
+	if _, err := manager.ProcessBlockWithResult(block); !errors.Is(err, ErrFatal) {
+		t.Errorf("ProcessBlockWithResult() error = %v, want ErrFatal", err)
+	}
+	if err := manager.ImportBlocks(nil); !errors.Is(err, ErrFatal) {
+		t.Errorf("ImportBlocks() error = %v, want ErrFatal", err)
+	}
+	if _, err := manager.ReadBlock(manager.GetActiveChain().GetTip()); !errors.Is(err, ErrFatal) {
+		t.Errorf("ReadBlock() error = %v, want ErrFatal", err)
+	}
+	if _, err := manager.SnapshotChain(); !errors.As(err, &fatal) {
+		t.Errorf("SnapshotChain() error = %v, want *FatalKernelError", err)
+	}
+}
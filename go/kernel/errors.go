@@ -1,16 +1,44 @@
 package kernel
 
+import "fmt"
+
 var (
 	ErrKernelInstantiate = &kernelError{"Failed to instantiate btck object"}
 
 	ErrKernelIndexOutOfBounds = &kernelError{"Index out of bounds"}
 
+	ErrKernelDeploymentNotEnabled = &kernelError{"Deployment is not enabled on this chain"}
+
+	ErrKernelUseAfterDestroy = &kernelError{"Object used after it or its owner was destroyed"}
+
+	ErrEventBusOverflow = &kernelError{"Event bus buffer overflowed"}
+
+	ErrNodeClosed = &kernelError{"Node has been closed"}
+
+	ErrInternal        = &kernelError{"Internal kernel error"}
+	ErrBlockNotFound   = &kernelError{"Block data not found"}
+	ErrBlockPruned     = &kernelError{"Block data has been pruned"}
+	ErrUndoMissing     = &kernelError{"Block undo data not found"}
+	ErrDeserialization = &kernelError{"Deserialization failed"}
+	ErrSerialization   = &kernelError{"Serialization failed"}
+	ErrInterrupted     = &kernelError{"Operation interrupted"}
+	ErrInvalidArgument = &kernelError{"Invalid argument"}
+	ErrFatal           = &kernelError{"Kernel reported a fatal error"}
+
+	ErrCompactTargetNegative = &kernelError{"Compact target is negative"}
+	ErrCompactTargetOverflow = &kernelError{"Compact target overflows 256 bits"}
+
 	ErrVerifyScriptVerifyTxInputIndex            = &ScriptVerifyError{"Transaction input index out of range"}
 	ErrVerifyScriptVerifyInvalidFlags            = &ScriptVerifyError{"Invalid script verification flags"}
 	ErrVerifyScriptVerifyInvalidFlagsCombination = &ScriptVerifyError{"Invalid combination of script verification flags"}
 	ErrVerifyScriptVerifySpentOutputsMismatch    = &ScriptVerifyError{"Spent outputs count mismatch"}
 	ErrVerifyScriptVerifySpentOutputsRequired    = &ScriptVerifyError{"Spent outputs required for verification"}
 	ErrVerifyScriptVerifyInvalid                 = &ScriptVerifyError{"Script verification failed"}
+
+	ErrSignatureHashScriptCodeRequired   = &SignatureHashError{"Script code required for this signature version"}
+	ErrSignatureHashSpentOutputsRequired = &SignatureHashError{"Spent outputs required for taproot signature hash"}
+	ErrSignatureHashSpentOutputsMismatch = &SignatureHashError{"Spent outputs count mismatch"}
+	ErrSignatureHashFailed               = &SignatureHashError{"Signature hash could not be computed"}
 )
 
 // check panics if ptr is nil, otherwise returns ptr unchanged; used when C calls are not expected to return null
@@ -36,7 +64,89 @@ func (e *kernelError) Error() string {
 
 func (e *kernelError) isKernelError() {}
 
-// InternalError is returned when a call to the underlying library fails.
+// ErrorCode classifies the cause of an Error. Each code has a matching sentinel
+// error, so that errors.Is(err, ErrBlockPruned) holds for an Error with code
+// ErrorCodeBlockPruned.
+type ErrorCode int
+
+const (
+	ErrorCodeInternal        ErrorCode = iota // The kernel reported a failure without a more specific cause
+	ErrorCodeBlockNotFound                    // The block data is not stored, e.g. because only its header is known
+	ErrorCodeBlockPruned                      // The block data was stored but has been pruned
+	ErrorCodeUndoMissing                      // The undo data of the block is not stored
+	ErrorCodeDeserialization                  // Input data could not be deserialized
+	ErrorCodeSerialization                    // An object could not be serialized
+	ErrorCodeInterrupted                      // The operation was interrupted, see Context.Interrupt
+	ErrorCodeInvalidArgument                  // An argument or combination of arguments is not supported
+	ErrorCodeFatal                            // The kernel reported a fatal error earlier, see ErrorPolicy
+)
+
+func (c ErrorCode) sentinel() error {
+	switch c {
+	case ErrorCodeBlockNotFound:
+		return ErrBlockNotFound
+	case ErrorCodeBlockPruned:
+		return ErrBlockPruned
+	case ErrorCodeUndoMissing:
+		return ErrUndoMissing
+	case ErrorCodeDeserialization:
+		return ErrDeserialization
+	case ErrorCodeSerialization:
+		return ErrSerialization
+	case ErrorCodeInterrupted:
+		return ErrInterrupted
+	case ErrorCodeInvalidArgument:
+		return ErrInvalidArgument
+	case ErrorCodeFatal:
+		return ErrFatal
+	default:
+		return ErrInternal
+	}
+}
+
+// String returns the message of the code's sentinel error.
+func (c ErrorCode) String() string {
+	return c.sentinel().Error()
+}
+
+// Error is returned by constructors and ChainstateManager methods when an operation
+// fails. Use errors.Is with the sentinel of its code (e.g. ErrBlockPruned) to check
+// the cause of a failure, and errors.As to access its details.
+type Error struct {
+	Op    string    // Operation that failed, e.g. "read block"
+	Code  ErrorCode // Cause of the failure
+	Block *BlockRef // Block the operation concerned, nil if none. Height is -1 if unknown.
+	Err   error     // Underlying error, nil if the kernel did not report one
+}
+
+func (e *Error) Error() string {
+	msg := "Failed to " + e.Op
+	if e.Block != nil {
+		msg += " " + e.Block.Hash.String()
+		if e.Block.Height >= 0 {
+			msg += fmt.Sprintf(" at height %d", e.Block.Height)
+		}
+	}
+	msg += ": " + e.Code.String()
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel of the error's code and the underlying error, if any.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Code.sentinel()}
+	}
+	return []error{e.Code.sentinel(), e.Err}
+}
+
+func (e *Error) isKernelError() {}
+
+// InternalError describes a failure of the underlying library.
+//
+// Deprecated: Failures are reported as *Error with code ErrorCodeInternal.
 type InternalError struct {
 	Msg string
 }
@@ -47,6 +157,8 @@ func (e *InternalError) Error() string {
 
 func (e *InternalError) isKernelError() {}
 
+// SerializationError describes why data could not be decoded. It is the underlying
+// error of an Error with code ErrorCodeDeserialization returned by the Decode methods.
 type SerializationError struct {
 	Msg string
 }
@@ -66,3 +178,59 @@ func (e *ScriptVerifyError) Error() string {
 }
 
 func (e *ScriptVerifyError) isKernelError() {}
+
+type SignatureHashError struct {
+	Msg string
+}
+
+func (e *SignatureHashError) Error() string {
+	return "Signature hash computation failed: " + e.Msg
+}
+
+func (e *SignatureHashError) isKernelError() {}
+
+// SubscriberPanicError describes a panic raised by a Dispatcher subscriber. It is
+// passed to the panic handler of the dispatcher after the panic was recovered.
+type SubscriberPanicError struct {
+	Subscriber string // Name the subscriber was registered with
+	Value      any    // Value passed to panic
+	Stack      []byte // Stack trace of the panicking goroutine
+}
+
+func (e *SubscriberPanicError) Error() string {
+	return fmt.Sprintf("Subscriber %q panicked: %v", e.Subscriber, e.Value)
+}
+
+func (e *SubscriberPanicError) isKernelError() {}
+
+// CallbackPanicError describes a panic raised by a Go callback invoked by the kernel,
+// such as a NotificationCallbacks function. It is passed to the CallbackPanicHandler and
+// returned by LastCallbackPanic.
+type CallbackPanicError struct {
+	Callback string // Name of the callback, e.g. "OnBlockTip"
+	Value    any    // Value passed to panic
+	Stack    []byte // Stack trace of the panicking goroutine
+}
+
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("Callback %s panicked: %v", e.Callback, e.Value)
+}
+
+func (e *CallbackPanicError) isKernelError() {}
+
+// FatalKernelError describes a fatal error reported by the kernel, after which the
+// chainstate can no longer be trusted. It is the underlying error of an Error with
+// code ErrorCodeFatal, see ErrorPolicy.
+type FatalKernelError struct {
+	Msg   string // Message reported by the kernel
+	Flush bool   // Whether the error was a failure to flush the chainstate to disk
+}
+
+func (e *FatalKernelError) Error() string {
+	if e.Flush {
+		return "Failed to flush chainstate: " + e.Msg
+	}
+	return "Fatal kernel error: " + e.Msg
+}
+
+func (e *FatalKernelError) isKernelError() {}
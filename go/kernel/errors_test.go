@@ -0,0 +1,56 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	var hash [32]byte
+	hash[0] = 0xab
+	err := error(&Error{
+		Op:    "read block",
+		Code:  ErrorCodeBlockPruned,
+		Block: &BlockRef{Hash: hash, Height: 5},
+	})
+
+	want := "Failed to read block 00000000000000000000000000000000000000000000000000000000000000ab at height 5: Block data has been pruned"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if !errors.Is(err, ErrBlockPruned) {
+		t.Errorf("errors.Is(err, ErrBlockPruned) = false")
+	}
+	if errors.Is(err, ErrBlockNotFound) {
+		t.Errorf("errors.Is(err, ErrBlockNotFound) = true")
+	}
+
+	var kernelErr *Error
+	if !errors.As(err, &kernelErr) || kernelErr.Block.Height != 5 {
+		t.Errorf("errors.As() did not expose the block of %v", err)
+	}
+	var ke KernelError
+	if !errors.As(err, &ke) {
+		t.Errorf("errors.As() did not match KernelError")
+	}
+}
+
+func TestErrorUnwrap(t *testing.T) {
+	err := error(&Error{Op: "import blocks", Code: ErrorCodeInterrupted, Err: context.Canceled})
+
+	if !errors.Is(err, ErrInterrupted) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v to match both ErrInterrupted and context.Canceled", err)
+	}
+	want := "Failed to import blocks: Operation interrupted: context canceled"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	unknownHeight := &Error{Op: "process block", Code: ErrorCodeInternal, Block: &BlockRef{Height: -1}}
+	if got := unknownHeight.Error(); got != "Failed to process block "+zeroHashHex+": Internal kernel error" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+const zeroHashHex = "0000000000000000000000000000000000000000000000000000000000000000"
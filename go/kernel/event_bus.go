@@ -0,0 +1,348 @@
+package kernel
+
+import (
+	"sync"
+)
+
+// Event is implemented by all events delivered by an EventBus.
+type Event interface {
+	isEvent()
+}
+
+// BlockRef identifies a block by its hash and height. Unlike a BlockTreeEntry it is
+// a plain value that remains valid after the chainstate manager is destroyed.
+type BlockRef struct {
+	Hash   BlockHashKey
+	Height int32
+}
+
+func newBlockRef(entry *BlockTreeEntry) BlockRef {
+	return BlockRef{Hash: entry.Hash().Key(), Height: entry.Height()}
+}
+
+// BlockTipEvent is delivered when the chain tip changed, see NotificationCallbacks.OnBlockTip.
+type BlockTipEvent struct {
+	State    SynchronizationState
+	Tip      BlockRef
+	Progress float64
+}
+
+// HeaderTipEvent is delivered when the best header changed, see NotificationCallbacks.OnHeaderTip.
+type HeaderTipEvent struct {
+	State     SynchronizationState
+	Height    int64
+	Timestamp int64
+	Presync   bool
+}
+
+// ProgressEvent reports the progress of a long-running operation, see NotificationCallbacks.OnProgress.
+type ProgressEvent struct {
+	Title     string
+	Percent   int
+	Resumable bool
+}
+
+// WarningSetEvent is delivered when a warning is raised, see NotificationCallbacks.OnWarningSet.
+type WarningSetEvent struct {
+	Warning Warning
+	Message string
+}
+
+// WarningUnsetEvent is delivered when a warning is cleared, see NotificationCallbacks.OnWarningUnset.
+type WarningUnsetEvent struct {
+	Warning Warning
+}
+
+// FlushErrorEvent is delivered when flushing state to disk failed, see NotificationCallbacks.OnFlushError.
+type FlushErrorEvent struct {
+	Message string
+}
+
+// FatalErrorEvent is delivered on an unrecoverable error, see NotificationCallbacks.OnFatalError.
+type FatalErrorEvent struct {
+	Message string
+}
+
+// BlockCheckedEvent is delivered when a block has been fully validated, see
+// ValidationInterfaceCallbacks.OnBlockChecked.
+//
+// Block is an owned reference the consumer may Destroy once done with it.
+type BlockCheckedEvent struct {
+	Block            *Block
+	ValidationMode   ValidationMode
+	ValidationResult BlockValidationResult
+}
+
+// PoWValidBlockEvent is delivered when a block extends the header chain and has valid
+// merkle roots, see ValidationInterfaceCallbacks.OnPoWValidBlock.
+//
+// Block is an owned reference the consumer may Destroy once done with it.
+type PoWValidBlockEvent struct {
+	Block *Block
+	Entry BlockRef
+}
+
+// BlockConnectedEvent is delivered when a block has been connected to the best chain,
+// see ValidationInterfaceCallbacks.OnBlockConnected.
+//
+// Block is an owned reference the consumer may Destroy once done with it.
+type BlockConnectedEvent struct {
+	Block *Block
+	Entry BlockRef
+}
+
+// BlockDisconnectedEvent is delivered when a block has been removed from the best chain
+// during a re-org, see ValidationInterfaceCallbacks.OnBlockDisconnected.
+//
+// Block is an owned reference the consumer may Destroy once done with it.
+type BlockDisconnectedEvent struct {
+	Block *Block
+	Entry BlockRef
+}
+
+func (BlockTipEvent) isEvent()          {}
+func (HeaderTipEvent) isEvent()         {}
+func (ProgressEvent) isEvent()          {}
+func (WarningSetEvent) isEvent()        {}
+func (WarningUnsetEvent) isEvent()      {}
+func (FlushErrorEvent) isEvent()        {}
+func (FatalErrorEvent) isEvent()        {}
+func (BlockCheckedEvent) isEvent()      {}
+func (PoWValidBlockEvent) isEvent()     {}
+func (BlockConnectedEvent) isEvent()    {}
+func (BlockDisconnectedEvent) isEvent() {}
+
+// BackpressurePolicy determines what an EventBus does with a new event when its buffer is full.
+type BackpressurePolicy int
+
+const (
+	// BackpressureDropOldest discards the oldest buffered event to make room for the new
+	// one. It is the default policy.
+	BackpressureDropOldest BackpressurePolicy = iota
+	// BackpressureError stops the bus: the event is discarded, the events channel is
+	// closed and Err returns ErrEventBusOverflow.
+	BackpressureError
+	// BackpressureBlock blocks the kernel callback, and with it validation, until the
+	// consumer has made room in the buffer.
+	//
+	// The kernel raises some callbacks while holding its chainstate lock, so while this
+	// policy is in use, consumers must not call into the chainstate manager, e.g. through
+	// ChainstateManager.GetActiveChain. Doing so deadlocks once the buffer is full.
+	BackpressureBlock
+)
+
+// EventBusOptions configures an EventBus.
+type EventBusOptions struct {
+	BufferSize int                // Capacity of the events channel, events are only delivered to a waiting consumer if 0
+	Policy     BackpressurePolicy // Behavior when the events channel is full, BackpressureDropOldest by default
+}
+
+// EventBus converts kernel notifications and validation interface callbacks into typed
+// events delivered on a Go channel, so that consumers can process them asynchronously
+// without blocking validation (unless the BackpressureBlock policy is used).
+//
+// Events carry copies of the callback data: blocks are owned references and block
+// tree entries are converted to BlockRef values, so they remain valid after the
+// callback returned.
+type EventBus struct {
+	policy BackpressurePolicy
+	events chan Event
+	done   chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	err     error
+	dropped uint64
+	senders sync.WaitGroup // senders blocked under BackpressureBlock
+}
+
+// NewEventBus creates an event bus. Register it with a context through Attach.
+//
+// Parameters:
+//   - options: Buffer size and backpressure policy of the bus
+func NewEventBus(options EventBusOptions) *EventBus {
+	return &EventBus{
+		policy: options.Policy,
+		events: make(chan Event, max(options.BufferSize, 0)),
+		done:   make(chan struct{}),
+	}
+}
+
+// Attach registers the bus as the notification and validation interface callbacks of
+// the context options. Contexts created from the options deliver their events to the bus.
+//
+// Parameters:
+//   - opts: Context options to register the bus with
+func (b *EventBus) Attach(opts *ContextOptions) {
+	opts.SetNotifications(b.NotificationCallbacks())
+	opts.SetValidationInterface(b.ValidationInterfaceCallbacks())
+}
+
+// Events returns the channel events are delivered on. It is closed by Close, or when
+// the bus overflows under the BackpressureError policy.
+func (b *EventBus) Events() <-chan Event {
+	return b.events
+}
+
+// Err returns ErrEventBusOverflow if the bus was stopped because its buffer overflowed
+// under the BackpressureError policy, and nil otherwise.
+func (b *EventBus) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
+
+// Dropped returns the number of events discarded because the buffer was full or the
+// bus was closed.
+func (b *EventBus) Dropped() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// Close stops the bus and closes the events channel. Events raised afterwards are
+// discarded, and kernel callbacks blocked on a full buffer are released.
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.done)
+	b.mu.Unlock()
+
+	// Blocked senders observe done and give up before the channel can be closed
+	b.senders.Wait()
+	close(b.events)
+}
+
+// NotificationCallbacks returns notification callbacks that publish to the bus.
+func (b *EventBus) NotificationCallbacks() *NotificationCallbacks {
+	notifications, _ := EventCallbacks(b.publish)
+	return notifications
+}
+
+// ValidationInterfaceCallbacks returns validation interface callbacks that publish to the bus.
+func (b *EventBus) ValidationInterfaceCallbacks() *ValidationInterfaceCallbacks {
+	_, validation := EventCallbacks(b.publish)
+	return validation
+}
+
+// EventCallbacks returns notification and validation interface callbacks that convert
+// each callback into the matching Event and pass it to handle. Blocks of validation
+// events are owned references that handle must eventually release, see ReleaseEvent.
+//
+// Parameters:
+//   - handle: Called with each event on the thread issuing the callback
+func EventCallbacks(handle func(Event)) (*NotificationCallbacks, *ValidationInterfaceCallbacks) {
+	notifications := &NotificationCallbacks{
+		OnBlockTip: func(state SynchronizationState, entry *BlockTreeEntry, progress float64) {
+			handle(BlockTipEvent{State: state, Tip: newBlockRef(entry), Progress: progress})
+		},
+		OnHeaderTip: func(state SynchronizationState, height int64, timestamp int64, presync bool) {
+			handle(HeaderTipEvent{State: state, Height: height, Timestamp: timestamp, Presync: presync})
+		},
+		OnProgress: func(title string, percent int, resumable bool) {
+			handle(ProgressEvent{Title: title, Percent: percent, Resumable: resumable})
+		},
+		OnWarningSet: func(warning Warning, message string) {
+			handle(WarningSetEvent{Warning: warning, Message: message})
+		},
+		OnWarningUnset: func(warning Warning) {
+			handle(WarningUnsetEvent{Warning: warning})
+		},
+		OnFlushError: func(message string) {
+			handle(FlushErrorEvent{Message: message})
+		},
+		OnFatalError: func(message string) {
+			handle(FatalErrorEvent{Message: message})
+		},
+	}
+	validation := &ValidationInterfaceCallbacks{
+		OnBlockChecked: func(block *Block, state *BlockValidationState) {
+			handle(BlockCheckedEvent{Block: block, ValidationMode: state.ValidationMode(), ValidationResult: state.ValidationResult()})
+		},
+		OnPoWValidBlock: func(block *Block, entry *BlockTreeEntry) {
+			handle(PoWValidBlockEvent{Block: block, Entry: newBlockRef(entry)})
+		},
+		OnBlockConnected: func(block *Block, entry *BlockTreeEntry) {
+			handle(BlockConnectedEvent{Block: block, Entry: newBlockRef(entry)})
+		},
+		OnBlockDisconnected: func(block *Block, entry *BlockTreeEntry) {
+			handle(BlockDisconnectedEvent{Block: block, Entry: newBlockRef(entry)})
+		},
+	}
+	return notifications, validation
+}
+
+// ReleaseEvent destroys the block an event carries, if any. It must not be called for
+// an event whose block was already destroyed by its consumer.
+func ReleaseEvent(event Event) {
+	switch e := event.(type) {
+	case BlockCheckedEvent:
+		e.Block.Destroy()
+	case PoWValidBlockEvent:
+		e.Block.Destroy()
+	case BlockConnectedEvent:
+		e.Block.Destroy()
+	case BlockDisconnectedEvent:
+		e.Block.Destroy()
+	}
+}
+
+func (b *EventBus) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		b.discard(event)
+		return
+	}
+
+	select {
+	case b.events <- event:
+		return
+	default:
+	}
+
+	switch b.policy {
+	case BackpressureError:
+		// No sender ever blocks under this policy, so the channel can be closed right away
+		b.discard(event)
+		b.err = ErrEventBusOverflow
+		b.closed = true
+		close(b.done)
+		close(b.events)
+	case BackpressureBlock:
+		// Wait without holding the lock, so that Close can release the sender
+		b.senders.Add(1)
+		b.mu.Unlock()
+		select {
+		case b.events <- event:
+		case <-b.done:
+			b.mu.Lock()
+			b.discard(event)
+			b.mu.Unlock()
+		}
+		b.senders.Done()
+		b.mu.Lock()
+	default:
+		select {
+		case oldest := <-b.events:
+			b.discard(oldest)
+		default:
+		}
+		select {
+		case b.events <- event:
+		default:
+			b.discard(event)
+		}
+	}
+}
+
+// discard releases the resources of an event that will not be delivered. Must be
+// called with b.mu held.
+func (b *EventBus) discard(event Event) {
+	b.dropped++
+	ReleaseEvent(event)
+}
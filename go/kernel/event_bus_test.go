@@ -0,0 +1,139 @@
+package kernel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus(EventBusOptions{BufferSize: 1024, Policy: BackpressureError})
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 3,
+		NotificationCallbacks:  bus.NotificationCallbacks(),
+		ValidationCallbacks:    bus.ValidationInterfaceCallbacks(),
+	}
+	suite.Setup(t)
+	bus.Close()
+
+	if err := bus.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	var connectedHeights []int32
+	var lastTip BlockRef
+	for event := range bus.Events() {
+		switch e := event.(type) {
+		case BlockConnectedEvent:
+			connectedHeights = append(connectedHeights, e.Entry.Height)
+			// The block remains usable after the callback returned
+			hash := e.Block.Hash()
+			if hash.Bytes() != e.Entry.Hash {
+				t.Errorf("Connected block hash %x does not match entry hash %x", hash.Bytes(), e.Entry.Hash)
+			}
+			hash.Destroy()
+			e.Block.Destroy()
+		case BlockCheckedEvent:
+			if e.ValidationMode != ValidationStateValid {
+				t.Errorf("Expected valid block, got validation mode %d", e.ValidationMode)
+			}
+			e.Block.Destroy()
+		case BlockTipEvent:
+			lastTip = e.Tip
+		}
+	}
+
+	if len(connectedHeights) != 3 || connectedHeights[0] != 1 || connectedHeights[2] != 3 {
+		t.Errorf("Connected heights = %v, want [1 2 3]", connectedHeights)
+	}
+	if lastTip.Height != 3 {
+		t.Errorf("Last tip height = %d, want 3", lastTip.Height)
+	}
+}
+
+func TestEventBusBackpressure(t *testing.T) {
+	t.Run("drop oldest", func(t *testing.T) {
+		bus := NewEventBus(EventBusOptions{BufferSize: 2, Policy: BackpressureDropOldest})
+		for i := 0; i < 5; i++ {
+			bus.publish(ProgressEvent{Percent: i})
+		}
+		bus.Close()
+
+		var percents []int
+		for event := range bus.Events() {
+			percents = append(percents, event.(ProgressEvent).Percent)
+		}
+		if len(percents) != 2 || percents[0] != 3 || percents[1] != 4 {
+			t.Errorf("Delivered %v, want [3 4]", percents)
+		}
+		if bus.Dropped() != 3 {
+			t.Errorf("Dropped() = %d, want 3", bus.Dropped())
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		// The zero value never blocks the kernel thread
+		bus := NewEventBus(EventBusOptions{})
+		bus.publish(ProgressEvent{Percent: 1})
+		bus.publish(ProgressEvent{Percent: 2})
+		if bus.Dropped() != 2 {
+			t.Errorf("Dropped() = %d without a waiting consumer, want 2", bus.Dropped())
+		}
+		bus.Close()
+	})
+
+	t.Run("error", func(t *testing.T) {
+		bus := NewEventBus(EventBusOptions{BufferSize: 1, Policy: BackpressureError})
+		bus.publish(ProgressEvent{Percent: 1})
+		bus.publish(ProgressEvent{Percent: 2})
+		bus.publish(ProgressEvent{Percent: 3})
+
+		if !errors.Is(bus.Err(), ErrEventBusOverflow) {
+			t.Errorf("Err() = %v, want %v", bus.Err(), ErrEventBusOverflow)
+		}
+		var count int
+		for range bus.Events() {
+			count++
+		}
+		if count != 1 {
+			t.Errorf("Delivered %d events before overflow, want 1", count)
+		}
+		bus.Close()
+	})
+
+	t.Run("block", func(t *testing.T) {
+		bus := NewEventBus(EventBusOptions{BufferSize: 1, Policy: BackpressureBlock})
+		bus.publish(ProgressEvent{Percent: 1})
+
+		published := make(chan struct{})
+		go func() {
+			bus.publish(ProgressEvent{Percent: 2})
+			close(published)
+		}()
+
+		select {
+		case <-published:
+			t.Fatal("publish() returned while the buffer was full")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		if event := <-bus.Events(); event.(ProgressEvent).Percent != 1 {
+			t.Errorf("Received %v, want percent 1", event)
+		}
+		<-published
+		if event := <-bus.Events(); event.(ProgressEvent).Percent != 2 {
+			t.Errorf("Received %v, want percent 2", event)
+		}
+
+		// Close releases a sender blocked on a full buffer
+		bus.publish(ProgressEvent{Percent: 3})
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			bus.Close()
+		}()
+		bus.publish(ProgressEvent{Percent: 4})
+		if bus.Dropped() != 1 {
+			t.Errorf("Dropped() = %d, want 1", bus.Dropped())
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package kernel
+
+import (
+	"fmt"
+	"io"
+)
+
+// HandleInfo describes a handle to a kernel object tracked in kerneldebug builds.
+type HandleInfo struct {
+	// ID is a sequence number assigned when the handle is created
+	ID uint64
+	// Type is the name of the wrapped kernel object, e.g. "block" or "transaction"
+	Type string
+	// Stack is the formatted Go call stack that created the handle
+	Stack string
+}
+
+// LiveHandles returns the handles that have been created and not yet released, in
+// creation order.
+//
+// Handles are only tracked when built with the kerneldebug build tag; otherwise the
+// result is always empty.
+func LiveHandles() []HandleInfo {
+	return handleSnapshot(false)
+}
+
+// FinalizedHandles returns the handles that were released by the garbage collector
+// instead of an explicit Destroy call, in creation order. Such handles are leaks that
+// finalizers happened to clean up.
+//
+// Handles are only tracked when built with the kerneldebug build tag; otherwise the
+// result is always empty.
+func FinalizedHandles() []HandleInfo {
+	return handleSnapshot(true)
+}
+
+// DumpLiveHandles writes a report of all live handles, including their creation stacks,
+// to w.
+//
+// Returns an error if writing to w fails.
+func DumpLiveHandles(w io.Writer) error {
+	handles := LiveHandles()
+	if _, err := fmt.Fprintf(w, "%d live kernel handles\n", len(handles)); err != nil {
+		return err
+	}
+	for _, info := range handles {
+		if _, err := fmt.Fprintf(w, "\n#%d %s created at:\n%s", info.ID, info.Type, info.Stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestingT is the subset of testing.TB used by VerifyNoHandleLeaks.
+type TestingT interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...any)
+}
+
+// VerifyNoHandleLeaks fails the test if any handle created after this call is not
+// released with Destroy by the time the test and its cleanups complete. Handles
+// reclaimed by finalizers instead of Destroy are reported as leaks too.
+//
+// Call it at the start of a test, before creating any kernel objects. It has no effect
+// unless built with the kerneldebug build tag.
+func VerifyNoHandleLeaks(t TestingT) {
+	t.Helper()
+	if !HandleTrackingEnabled {
+		return
+	}
+	since := nextHandleID()
+	t.Cleanup(func() {
+		t.Helper()
+		for _, info := range LiveHandles() {
+			if info.ID >= since {
+				t.Errorf("kernel handle #%d (%s) leaked, created at:\n%s", info.ID, info.Type, info.Stack)
+			}
+		}
+		for _, info := range FinalizedHandles() {
+			if info.ID >= since {
+				t.Errorf("kernel handle #%d (%s) was released by a finalizer instead of Destroy, created at:\n%s", info.ID, info.Type, info.Stack)
+			}
+		}
+	})
+}
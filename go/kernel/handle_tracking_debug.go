@@ -0,0 +1,101 @@
+//go:build kerneldebug
+
+package kernel
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// HandleTrackingEnabled reports whether the package was built with the kerneldebug
+// build tag, in which case every handle is registered with its creation stack.
+const HandleTrackingEnabled = true
+
+type handleRecord struct {
+	id  uint64
+	typ string
+	pcs []uintptr
+}
+
+func (r *handleRecord) info() HandleInfo {
+	var stack strings.Builder
+	frames := runtime.CallersFrames(r.pcs)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&stack, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return HandleInfo{ID: r.id, Type: r.typ, Stack: stack.String()}
+}
+
+var handleRegistry = struct {
+	sync.Mutex
+	nextID    uint64
+	live      map[unsafe.Pointer]*handleRecord
+	finalized []*handleRecord
+}{live: make(map[unsafe.Pointer]*handleRecord)}
+
+// trackHandle registers the kernel object ptr. The recorded stack skips trackHandle,
+// newHandle/newUniqueHandle and the unexported newX wrapper, so it starts at the
+// exported function that created the object.
+func trackHandle(ptr unsafe.Pointer, funcs destroyableFuncs) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(4, pcs)
+
+	typ := strings.TrimSuffix(strings.TrimPrefix(fmt.Sprintf("%T", funcs), "kernel."), "CFuncs")
+
+	handleRegistry.Lock()
+	defer handleRegistry.Unlock()
+	handleRegistry.live[ptr] = &handleRecord{id: handleRegistry.nextID, typ: typ, pcs: pcs[:n]}
+	handleRegistry.nextID++
+}
+
+func untrackHandle(ptr unsafe.Pointer) {
+	handleRegistry.Lock()
+	defer handleRegistry.Unlock()
+	delete(handleRegistry.live, ptr)
+}
+
+func handleFinalized(ptr unsafe.Pointer) {
+	handleRegistry.Lock()
+	defer handleRegistry.Unlock()
+	if record, ok := handleRegistry.live[ptr]; ok {
+		handleRegistry.finalized = append(handleRegistry.finalized, record)
+		delete(handleRegistry.live, ptr)
+	}
+}
+
+func nextHandleID() uint64 {
+	handleRegistry.Lock()
+	defer handleRegistry.Unlock()
+	return handleRegistry.nextID
+}
+
+func handleSnapshot(finalized bool) []HandleInfo {
+	handleRegistry.Lock()
+	var records []*handleRecord
+	if finalized {
+		records = slices.Clone(handleRegistry.finalized)
+	} else {
+		for _, record := range handleRegistry.live {
+			records = append(records, record)
+		}
+	}
+	handleRegistry.Unlock()
+
+	slices.SortFunc(records, func(a, b *handleRecord) int {
+		return cmp.Compare(a.id, b.id)
+	})
+	infos := make([]HandleInfo, len(records))
+	for i, record := range records {
+		infos[i] = record.info()
+	}
+	return infos
+}
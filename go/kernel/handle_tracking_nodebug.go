@@ -0,0 +1,21 @@
+//go:build !kerneldebug
+
+package kernel
+
+import (
+	"unsafe"
+)
+
+// HandleTrackingEnabled reports whether the package was built with the kerneldebug
+// build tag, in which case every handle is registered with its creation stack.
+const HandleTrackingEnabled = false
+
+func trackHandle(unsafe.Pointer, destroyableFuncs) {}
+
+func untrackHandle(unsafe.Pointer) {}
+
+func handleFinalized(unsafe.Pointer) {}
+
+func nextHandleID() uint64 { return 0 }
+
+func handleSnapshot(bool) []HandleInfo { return nil }
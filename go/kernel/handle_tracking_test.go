@@ -0,0 +1,157 @@
+//go:build kerneldebug
+
+package kernel
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLiveHandles(t *testing.T) {
+	VerifyNoHandleLeaks(t)
+	since := nextHandleID()
+
+	scriptPubkey := NewScriptPubkey([]byte{0x51})
+	defer scriptPubkey.Destroy()
+	output := NewTransactionOutput(scriptPubkey, 1000)
+
+	if !hasLiveHandle("transactionOutput", since) {
+		t.Fatal("Expected a live transactionOutput handle")
+	}
+	var report strings.Builder
+	if err := DumpLiveHandles(&report); err != nil {
+		t.Fatalf("DumpLiveHandles() error = %v", err)
+	}
+	if !strings.Contains(report.String(), "transactionOutput created at:") || !strings.Contains(report.String(), "TestLiveHandles") {
+		t.Errorf("DumpLiveHandles() report missing handle or creation stack:\n%s", report.String())
+	}
+
+	output.Destroy()
+	if hasLiveHandle("transactionOutput", since) {
+		t.Error("Expected the transactionOutput handle to be released after Destroy")
+	}
+}
+
+func TestVerifyNoHandleLeaks(t *testing.T) {
+	leaked := &recordingT{}
+	VerifyNoHandleLeaks(leaked)
+	scriptPubkey := NewScriptPubkey([]byte{0x51})
+	leaked.runCleanups()
+	if len(leaked.errors) != 1 || !strings.Contains(leaked.errors[0], "(scriptPubkey) leaked") {
+		t.Errorf("VerifyNoHandleLeaks() reported %q, want a single scriptPubkey leak", leaked.errors)
+	}
+	scriptPubkey.Destroy()
+
+	finalized := &recordingT{}
+	finalizedBefore := len(FinalizedHandles())
+	VerifyNoHandleLeaks(finalized)
+	func() {
+		_ = NewScriptPubkey([]byte{0x51})
+	}()
+	// Finalizers run asynchronously after a collection
+	for i := 0; i < 100 && len(FinalizedHandles()) == finalizedBefore; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	finalized.runCleanups()
+	if len(finalized.errors) != 1 || !strings.Contains(finalized.errors[0], "released by a finalizer") {
+		t.Errorf("VerifyNoHandleLeaks() reported %q, want a single finalizer release", finalized.errors)
+	}
+
+	clean := &recordingT{}
+	VerifyNoHandleLeaks(clean)
+	NewScriptPubkey([]byte{0x51}).Destroy()
+	clean.runCleanups()
+	if len(clean.errors) != 0 {
+		t.Errorf("VerifyNoHandleLeaks() reported %q, want no leaks", clean.errors)
+	}
+}
+
+// hasLiveHandle reports whether a handle of type typ created since the handle ID since
+// is live, ignoring handles other tests left to the finalizer.
+func hasLiveHandle(typ string, since uint64) bool {
+	for _, info := range LiveHandles() {
+		if info.Type == typ && info.ID >= since {
+			return true
+		}
+	}
+	return false
+}
+
+// recordingT collects the failures reported by VerifyNoHandleLeaks
+type recordingT struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) runCleanups() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestNodeReleasesHandles(t *testing.T) {
+	VerifyNoHandleLeaks(t)
+	since := nextHandleID()
+
+	// The chainstate manager and its options hold references to the context, which
+	// must be released when they are destroyed
+	node, err := OpenNode(t.TempDir(), WithChain(ChainTypeRegtest), WithInMemoryDBs())
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+	if err := node.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if hasLiveHandle("context", since) {
+		t.Error("Expected no live context handle after Close")
+	}
+}
+
+func TestDroppedCallbacksReleaseBlocks(t *testing.T) {
+	VerifyNoHandleLeaks(t)
+
+	// The first callback blocks the worker and the second fills the queue, so the
+	// blocks captured by the remaining callbacks are dropped
+	release := make(chan struct{})
+	node, err := OpenNode(t.TempDir(), WithChain(ChainTypeRegtest), WithInMemoryDBs(), WithAsyncCallbacks(1),
+		WithValidationInterface(&ValidationInterfaceCallbacks{
+			OnBlockConnected: func(block *Block, entry *BlockTreeEntry) {
+				<-release
+				block.Destroy()
+			},
+		}))
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+	for _, line := range regtestBlockLines(t)[:5] {
+		block, err := NewBlock(mustDecodeHex(t, line))
+		if err != nil {
+			t.Fatalf("NewBlock() error = %v", err)
+		}
+		if ok, _ := node.ChainstateManager().ProcessBlock(block); !ok {
+			t.Fatal("ProcessBlock() failed")
+		}
+		block.Destroy()
+	}
+	close(release)
+	if dropped := node.Context().DroppedCallbacks(); dropped == 0 {
+		t.Error("Expected callbacks to be dropped from the full queue")
+	}
+	if err := node.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package kernel
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// hashString returns the hex encoding of a 32-byte hash in display byte order, which
+// is the reverse of the internal byte order it is stored and serialized in.
+func hashString(hash [32]byte) string {
+	return hex.EncodeToString(reverseHash(hash))
+}
+
+// parseHash parses a 64 character hex string in display byte order into a hash in
+// internal byte order.
+func parseHash(op string, s string) ([32]byte, error) {
+	var hash [32]byte
+	if len(s) != 2*len(hash) {
+		return hash, &Error{Op: op, Code: ErrorCodeDeserialization, Err: fmt.Errorf("invalid hash length %d, want %d hex characters", len(s), 2*len(hash))}
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return hash, &Error{Op: op, Code: ErrorCodeDeserialization, Err: err}
+	}
+	copy(hash[:], decoded)
+	return [32]byte(reverseHash(hash)), nil
+}
+
+// unmarshalHashJSON parses a JSON string holding a hash in display byte order.
+func unmarshalHashJSON(op string, data []byte) ([32]byte, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return [32]byte{}, &Error{Op: op, Code: ErrorCodeDeserialization, Err: err}
+	}
+	return parseHash(op, s)
+}
+
+// reverseHash converts a hash between internal and display byte order.
+func reverseHash(hash [32]byte) []byte {
+	reversed := make([]byte, len(hash))
+	for i, b := range hash {
+		reversed[len(hash)-1-i] = b
+	}
+	return reversed
+}
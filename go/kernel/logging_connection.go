@@ -38,6 +38,8 @@ type LoggingConnection struct {
 
 //export go_log_callback_bridge
 func go_log_callback_bridge(user_data unsafe.Pointer, message *C.char, message_len C.size_t) {
+	defer recoverCallbackPanic("LogCallback")
+
 	handle := cgo.Handle(user_data)
 	callback := handle.Value().(LogCallback)
 	goMessage := C.GoStringN(message, C.int(message_len))
@@ -59,7 +61,7 @@ func NewLoggingConnection(callback LogCallback) (*LoggingConnection, error) {
 		unsafe.Pointer(callbackHandle), C.btck_DestroyCallback(C.go_delete_handle))
 	if ptr == nil {
 		callbackHandle.Delete()
-		return nil, &InternalError{"Failed to create logging connection"}
+		return nil, &Error{Op: "create logging connection", Code: ErrorCodeInternal}
 	}
 	h := newUniqueHandle(unsafe.Pointer(ptr), loggingConnectionCFuncs{})
 	return &LoggingConnection{uniqueHandle: h}, nil
@@ -0,0 +1,242 @@
+package kernel
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+)
+
+// Node bundles a kernel Context and a ChainstateManager created from it, taking care
+// of creating the intermediate options objects and of destroying everything in a safe
+// order on Close.
+type Node struct {
+	context *Context
+	manager *ChainstateManager
+
+	mu      sync.Mutex
+	closed  bool
+	running sync.WaitGroup // operations started through the node
+}
+
+type nodeConfig struct {
+	chainType            ChainType
+	blocksDir            string
+	workerThreads        int
+	notifications        *NotificationCallbacks
+	validation           *ValidationInterfaceCallbacks
+	asyncCallbacks       bool
+	callbackQueueSize    int
+	errorPolicy          ErrorPolicy
+	blockTreeDBInMemory  bool
+	chainstateDBInMemory bool
+	wipeBlockTree        bool
+	wipeChainstate       bool
+}
+
+// NodeOption configures a Node created with OpenNode.
+type NodeOption func(*nodeConfig)
+
+// WithChain selects the chain of the node. Defaults to ChainTypeMainnet.
+func WithChain(chainType ChainType) NodeOption {
+	return func(c *nodeConfig) { c.chainType = chainType }
+}
+
+// WithBlocksDir sets the directory block files are stored in. Defaults to the blocks
+// subdirectory of the data directory.
+func WithBlocksDir(blocksDir string) NodeOption {
+	return func(c *nodeConfig) { c.blocksDir = blocksDir }
+}
+
+// WithWorkerThreads sets the number of threads used for parallel script verification,
+// see ChainstateManagerOptions.SetWorkerThreads.
+func WithWorkerThreads(threads int) NodeOption {
+	return func(c *nodeConfig) { c.workerThreads = threads }
+}
+
+// WithNotifications registers kernel notification callbacks, see
+// ContextOptions.SetNotifications.
+func WithNotifications(callbacks *NotificationCallbacks) NodeOption {
+	return func(c *nodeConfig) { c.notifications = callbacks }
+}
+
+// WithValidationInterface registers validation interface callbacks, see
+// ContextOptions.SetValidationInterface.
+func WithValidationInterface(callbacks *ValidationInterfaceCallbacks) NodeOption {
+	return func(c *nodeConfig) { c.validation = callbacks }
+}
+
+// WithAsyncCallbacks runs notification and validation interface callbacks on a
+// dedicated goroutine, see ContextOptions.SetAsyncCallbacks. Close waits for queued
+// callbacks before tearing the node down.
+func WithAsyncCallbacks(queueSize int) NodeOption {
+	return func(c *nodeConfig) {
+		c.asyncCallbacks = true
+		c.callbackQueueSize = queueSize
+	}
+}
+
+// WithErrorPolicy sets how the node reacts to fatal kernel errors, see
+// ContextOptions.SetErrorPolicy.
+func WithErrorPolicy(policy ErrorPolicy) NodeOption {
+	return func(c *nodeConfig) { c.errorPolicy = policy }
+}
+
+// WithInMemoryDBs keeps the block tree and chainstate databases in memory instead of
+// on disk, e.g. for tests. Block files are still written to the blocks directory.
+func WithInMemoryDBs() NodeOption {
+	return func(c *nodeConfig) {
+		c.blockTreeDBInMemory = true
+		c.chainstateDBInMemory = true
+	}
+}
+
+// WithWipeDBs wipes the databases on startup, see ChainstateManagerOptions.SetWipeDBs.
+func WithWipeDBs(wipeBlockTree, wipeChainstate bool) NodeOption {
+	return func(c *nodeConfig) {
+		c.wipeBlockTree = wipeBlockTree
+		c.wipeChainstate = wipeChainstate
+	}
+}
+
+// OpenNode creates a context and a chainstate manager for the data directory and
+// loads the chainstate, so that blocks can be processed right away.
+//
+// Parameters:
+//   - dataDir: Directory the databases are stored in
+//   - options: Options overriding the defaults, e.g. WithChain(ChainTypeRegtest)
+//
+// Returns an error if any of the objects cannot be created or the chainstate cannot be
+// loaded. Everything created up to that point is destroyed.
+func OpenNode(dataDir string, options ...NodeOption) (*Node, error) {
+	config := nodeConfig{
+		chainType: ChainTypeMainnet,
+		blocksDir: filepath.Join(dataDir, "blocks"),
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	chainParams, err := NewChainParameters(config.chainType)
+	if err != nil {
+		return nil, err
+	}
+	defer chainParams.Destroy()
+
+	contextOpts := NewContextOptions()
+	defer contextOpts.Destroy()
+	contextOpts.SetChainParams(chainParams)
+	if config.notifications != nil {
+		contextOpts.SetNotifications(config.notifications)
+	}
+	if config.validation != nil {
+		contextOpts.SetValidationInterface(config.validation)
+	}
+	if config.asyncCallbacks {
+		contextOpts.SetAsyncCallbacks(config.callbackQueueSize)
+	}
+	contextOpts.SetErrorPolicy(config.errorPolicy)
+
+	ctx, err := NewContext(contextOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := newNodeChainstateManager(ctx, dataDir, &config)
+	if err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+
+	// Load the chainstate, initializing empty databases with the genesis block
+	if err := manager.ImportBlocks(nil); err != nil {
+		ctx.Drain()
+		manager.Destroy()
+		ctx.Drain()
+		ctx.Destroy()
+		return nil, err
+	}
+	return &Node{context: ctx, manager: manager}, nil
+}
+
+func newNodeChainstateManager(ctx *Context, dataDir string, config *nodeConfig) (*ChainstateManager, error) {
+	opts, err := NewChainstateManagerOptions(ctx, dataDir, config.blocksDir)
+	if err != nil {
+		return nil, err
+	}
+	defer opts.Destroy()
+
+	opts.SetWorkerThreads(config.workerThreads)
+	opts.UpdateBlockTreeDBInMemory(config.blockTreeDBInMemory)
+	opts.UpdateChainstateDBInMemory(config.chainstateDBInMemory)
+	if config.wipeBlockTree || config.wipeChainstate {
+		if err := opts.SetWipeDBs(config.wipeBlockTree, config.wipeChainstate); err != nil {
+			return nil, err
+		}
+	}
+	return NewChainstateManager(opts)
+}
+
+// Context returns the kernel context of the node. It is destroyed by Close.
+func (n *Node) Context() *Context {
+	return n.context
+}
+
+// ChainstateManager returns the chainstate manager of the node. It is destroyed by
+// Close, so operations called on it directly must have returned before Close is called.
+func (n *Node) ChainstateManager() *ChainstateManager {
+	return n.manager
+}
+
+// ImportBlocks imports block files or reindexes like ChainstateManager.ImportBlocksContext.
+// Unlike an import started on the chainstate manager directly, it is interrupted by
+// Close, which waits for it to return.
+//
+// Parameters:
+//   - ctx: Go context whose cancellation interrupts the import
+//   - blockFilePaths: Array of full filesystem paths to block files to import (can be empty)
+//
+// Returns ErrNodeClosed if the node has been closed.
+func (n *Node) ImportBlocks(ctx context.Context, blockFilePaths []string) error {
+	if err := n.begin(); err != nil {
+		return err
+	}
+	defer n.running.Done()
+	return n.manager.ImportBlocksContext(ctx, blockFilePaths)
+}
+
+// begin registers an operation so that Close waits for it.
+func (n *Node) begin() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return ErrNodeClosed
+	}
+	n.running.Add(1)
+	return nil
+}
+
+// Close shuts the node down: it interrupts operations started through the node and
+// waits for them to return, then destroys the chainstate manager, which flushes the
+// chainstate to disk, and finally the context. Callbacks queued for asynchronous
+// dispatch are drained before each of these objects is destroyed. Calling Close more
+// than once has no effect.
+//
+// Returns an error if the running operations could not be interrupted. The node is
+// torn down regardless.
+func (n *Node) Close() error {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return nil
+	}
+	n.closed = true
+	n.mu.Unlock()
+
+	err := n.context.Interrupt()
+	n.running.Wait()
+	n.context.Drain()
+	n.manager.Destroy()
+	n.context.Drain()
+	n.context.Destroy()
+	return err
+}
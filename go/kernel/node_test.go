@@ -0,0 +1,80 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNode(t *testing.T) {
+	dataDir := t.TempDir()
+	blockLines := regtestBlockLines(t)
+
+	var tips int
+	notifications := &NotificationCallbacks{
+		OnBlockTip: func(state SynchronizationState, entry *BlockTreeEntry, progress float64) {
+			tips++
+		},
+	}
+
+	node, err := OpenNode(dataDir, WithChain(ChainTypeRegtest), WithWorkerThreads(1), WithNotifications(notifications))
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+
+	if tip := node.ChainstateManager().GetActiveChain().GetTip(); tip == nil || tip.Height() != 0 {
+		t.Fatalf("Expected genesis tip after OpenNode()")
+	}
+
+	const processed = 5
+	for i := 0; i < processed; i++ {
+		block, err := NewBlock(mustDecodeHex(t, blockLines[i]))
+		if err != nil {
+			t.Fatalf("NewBlock() error = %v", err)
+		}
+		ok, _ := node.ChainstateManager().ProcessBlock(block)
+		block.Destroy()
+		if !ok {
+			t.Fatalf("ProcessBlock() failed for block %d", i+1)
+		}
+	}
+	if tips != processed {
+		t.Errorf("Expected %d block tip notifications, got %d", processed, tips)
+	}
+
+	if err := node.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := node.Close(); err != nil {
+		t.Errorf("Second Close() error = %v", err)
+	}
+	if err := node.ImportBlocks(context.Background(), nil); !errors.Is(err, ErrNodeClosed) {
+		t.Errorf("ImportBlocks() after Close() error = %v, want ErrNodeClosed", err)
+	}
+
+	// Close flushed the chainstate, so reopening the data directory restores the tip
+	node, err = OpenNode(dataDir, WithChain(ChainTypeRegtest))
+	if err != nil {
+		t.Fatalf("Reopening OpenNode() error = %v", err)
+	}
+	defer node.Close()
+
+	if tip := node.ChainstateManager().GetActiveChain().GetTip(); tip == nil || tip.Height() != processed {
+		t.Errorf("Expected tip at height %d after reopening", processed)
+	}
+}
+
+func TestNodeInMemory(t *testing.T) {
+	node, err := OpenNode(t.TempDir(), WithChain(ChainTypeRegtest), WithInMemoryDBs())
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+	defer node.Close()
+
+	if err := node.ImportBlocks(context.Background(), nil); err != nil {
+		t.Errorf("ImportBlocks() error = %v", err)
+	}
+	if node.Context() == nil {
+		t.Error("Context() returned nil")
+	}
+}
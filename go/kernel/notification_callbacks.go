@@ -37,81 +37,110 @@ const (
 	WarningLargeWorkInvalidChain    Warning = C.btck_Warning_LARGE_WORK_INVALID_CHAIN
 )
 
+// notificationTarget is the user data of the notification bridges.
+type notificationTarget struct {
+	callbacks *NotificationCallbacks
+	queue     *callbackQueue
+	failure   *failureState
+}
+
 //export go_notify_block_tip_bridge
 func go_notify_block_tip_bridge(user_data unsafe.Pointer, state C.btck_SynchronizationState, entry *C.btck_BlockTreeEntry, verification_progress C.double) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	defer recoverCallbackPanic("OnBlockTip")
+
+	target := cgo.Handle(user_data).Value().(*notificationTarget)
+	callbacks := target.callbacks
 
 	if callbacks.OnBlockTip != nil {
 		goState := SynchronizationState(state)
 		goEntry := &BlockTreeEntry{ptr: (*C.btck_BlockTreeEntry)(unsafe.Pointer(entry))}
-		callbacks.OnBlockTip(goState, goEntry, float64(verification_progress))
+		progress := float64(verification_progress)
+		target.queue.dispatch("OnBlockTip", func() { callbacks.OnBlockTip(goState, goEntry, progress) })
 	}
 }
 
 //export go_notify_header_tip_bridge
 func go_notify_header_tip_bridge(user_data unsafe.Pointer, state C.btck_SynchronizationState, height C.int64_t, timestamp C.int64_t, presync C.int) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	defer recoverCallbackPanic("OnHeaderTip")
+
+	target := cgo.Handle(user_data).Value().(*notificationTarget)
+	callbacks := target.callbacks
 
 	if callbacks.OnHeaderTip != nil {
 		goState := SynchronizationState(state)
-		callbacks.OnHeaderTip(goState, int64(height), int64(timestamp), presync != 0)
+		goHeight, goTimestamp, goPresync := int64(height), int64(timestamp), presync != 0
+		target.queue.dispatch("OnHeaderTip", func() { callbacks.OnHeaderTip(goState, goHeight, goTimestamp, goPresync) })
 	}
 }
 
 //export go_notify_progress_bridge
 func go_notify_progress_bridge(user_data unsafe.Pointer, title *C.char, title_len C.size_t, progress_percent C.int, resume_possible C.int) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	defer recoverCallbackPanic("OnProgress")
+
+	target := cgo.Handle(user_data).Value().(*notificationTarget)
+	callbacks := target.callbacks
 
 	if callbacks.OnProgress != nil {
 		goTitle := C.GoStringN(title, C.int(title_len))
-		callbacks.OnProgress(goTitle, int(progress_percent), resume_possible != 0)
+		goPercent, goResumable := int(progress_percent), resume_possible != 0
+		target.queue.dispatch("OnProgress", func() { callbacks.OnProgress(goTitle, goPercent, goResumable) })
 	}
 }
 
 //export go_notify_warning_set_bridge
 func go_notify_warning_set_bridge(user_data unsafe.Pointer, warning C.btck_Warning, message *C.char, message_len C.size_t) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	defer recoverCallbackPanic("OnWarningSet")
+
+	target := cgo.Handle(user_data).Value().(*notificationTarget)
+	callbacks := target.callbacks
 
 	if callbacks.OnWarningSet != nil {
 		goWarning := Warning(warning)
 		goMessage := C.GoStringN(message, C.int(message_len))
-		callbacks.OnWarningSet(goWarning, goMessage)
+		target.queue.dispatch("OnWarningSet", func() { callbacks.OnWarningSet(goWarning, goMessage) })
 	}
 }
 
 //export go_notify_warning_unset_bridge
 func go_notify_warning_unset_bridge(user_data unsafe.Pointer, warning C.btck_Warning) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	defer recoverCallbackPanic("OnWarningUnset")
+
+	target := cgo.Handle(user_data).Value().(*notificationTarget)
+	callbacks := target.callbacks
 
 	if callbacks.OnWarningUnset != nil {
 		goWarning := Warning(warning)
-		callbacks.OnWarningUnset(goWarning)
+		target.queue.dispatch("OnWarningUnset", func() { callbacks.OnWarningUnset(goWarning) })
 	}
 }
 
 //export go_notify_flush_error_bridge
 func go_notify_flush_error_bridge(user_data unsafe.Pointer, message *C.char, message_len C.size_t) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	defer recoverCallbackPanic("OnFlushError")
+
+	target := cgo.Handle(user_data).Value().(*notificationTarget)
+	callbacks := target.callbacks
+
+	goMessage := C.GoStringN(message, C.int(message_len))
+	// Fail the context before the kernel continues, even if callbacks are queued
+	target.failure.fail(&FatalKernelError{Msg: goMessage, Flush: true})
 
 	if callbacks.OnFlushError != nil {
-		goMessage := C.GoStringN(message, C.int(message_len))
-		callbacks.OnFlushError(goMessage)
+		target.queue.dispatch("OnFlushError", func() { callbacks.OnFlushError(goMessage) })
 	}
 }
 
 //export go_notify_fatal_error_bridge
 func go_notify_fatal_error_bridge(user_data unsafe.Pointer, message *C.char, message_len C.size_t) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	defer recoverCallbackPanic("OnFatalError")
+
+	target := cgo.Handle(user_data).Value().(*notificationTarget)
+	callbacks := target.callbacks
+
+	goMessage := C.GoStringN(message, C.int(message_len))
+	target.failure.fail(&FatalKernelError{Msg: goMessage})
 
 	if callbacks.OnFatalError != nil {
-		goMessage := C.GoStringN(message, C.int(message_len))
-		callbacks.OnFatalError(goMessage)
+		target.queue.dispatch("OnFatalError", func() { callbacks.OnFatalError(goMessage) })
 	}
 }
@@ -0,0 +1,92 @@
+package kernel
+
+import "math/big"
+
+// CompactToTarget expands a compact target, as found in the nBits field of a block
+// header, into the full 256-bit target.
+//
+// The encoding matches arith_uint256::SetCompact: the most significant byte is the
+// size in bytes of the target and the lower 23 bits are its mantissa.
+//
+// Parameters:
+//   - bits: Compact representation of the target
+//
+// Returns an error with code ErrorCodeInvalidArgument wrapping ErrCompactTargetNegative
+// if the sign bit is set on a non-zero mantissa, or ErrCompactTargetOverflow if the
+// target does not fit in 256 bits.
+func CompactToTarget(bits uint32) (*big.Int, error) {
+	size := bits >> 24
+	word := bits & 0x007fffff
+
+	target := new(big.Int)
+	if size <= 3 {
+		target.SetUint64(uint64(word >> (8 * (3 - size))))
+	} else {
+		target.Lsh(new(big.Int).SetUint64(uint64(word)), uint(8*(size-3)))
+	}
+
+	if word != 0 && bits&0x00800000 != 0 {
+		return nil, &Error{Op: "expand compact target", Code: ErrorCodeInvalidArgument, Err: ErrCompactTargetNegative}
+	}
+	if word != 0 && (size > 34 || (word > 0xff && size > 33) || (word > 0xffff && size > 32)) {
+		return nil, &Error{Op: "expand compact target", Code: ErrorCodeInvalidArgument, Err: ErrCompactTargetOverflow}
+	}
+	return target, nil
+}
+
+// TargetToCompact encodes a 256-bit target into its compact representation, the
+// inverse of CompactToTarget. Precision beyond the 23-bit mantissa is truncated.
+//
+// Parameters:
+//   - target: Non-negative target of at most 256 bits
+func TargetToCompact(target *big.Int) uint32 {
+	size := uint32((target.BitLen() + 7) / 8)
+	var compact uint32
+	if size <= 3 {
+		compact = uint32(target.Uint64() << (8 * (3 - size)))
+	} else {
+		compact = uint32(new(big.Int).Rsh(target, uint(8*(size-3))).Uint64())
+	}
+	// The 0x00800000 bit denotes the sign, so if it is already set divide the
+	// mantissa by 256 and increase the exponent.
+	if compact&0x00800000 != 0 {
+		compact >>= 8
+		size++
+	}
+	return compact | size<<24
+}
+
+// CompactToDifficulty returns the difficulty of a compact target as a multiple of
+// the minimum mainnet difficulty, as reported by the getdifficulty RPC.
+//
+// Parameters:
+//   - bits: Compact representation of the target
+func CompactToDifficulty(bits uint32) float64 {
+	shift := (bits >> 24) & 0xff
+	difficulty := float64(0x0000ffff) / float64(bits&0x00ffffff)
+	for ; shift < 29; shift++ {
+		difficulty *= 256.0
+	}
+	for ; shift > 29; shift-- {
+		difficulty /= 256.0
+	}
+	return difficulty
+}
+
+// CompactToWork returns the expected number of hashes required to find a block
+// meeting the compact target, i.e. 2**256 / (target+1). Summing the work of all
+// blocks in a chain yields its total chain work.
+//
+// Invalid and zero targets contribute no work, consistent with the block proof
+// calculation used by consensus.
+//
+// Parameters:
+//   - bits: Compact representation of the target
+func CompactToWork(bits uint32) *big.Int {
+	target, err := CompactToTarget(bits)
+	if err != nil || target.Sign() == 0 {
+		return new(big.Int)
+	}
+	numerator := new(big.Int).Lsh(big.NewInt(1), 256)
+	return numerator.Div(numerator, target.Add(target, big.NewInt(1)))
+}
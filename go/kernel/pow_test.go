@@ -0,0 +1,76 @@
+package kernel
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestCompactToTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    uint32
+		want    string
+		wantErr error
+	}{
+		{"zero mantissa", 0x01003456, "0", nil},
+		{"small size shifts mantissa right", 0x01123456, "12", nil},
+		{"mainnet pow limit", 0x1d00ffff, "ffff0000000000000000000000000000000000000000000000000000", nil},
+		{"regtest pow limit", 0x207fffff, "7fffff0000000000000000000000000000000000000000000000000000000000", nil},
+		{"negative", 0x04923456, "", ErrCompactTargetNegative},
+		{"negative zero is zero", 0x04800000, "0", nil},
+		{"overflow", 0xff123456, "", ErrCompactTargetOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := CompactToTarget(tt.bits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompactToTarget(%#x) error = %v, want %v", tt.bits, err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("CompactToTarget(%#x) error = %v, want ErrInvalidArgument", tt.bits, err)
+				}
+				return
+			}
+			if target.Text(16) != tt.want {
+				t.Errorf("CompactToTarget(%#x) = %s, want %s", tt.bits, target.Text(16), tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetToCompact(t *testing.T) {
+	for _, bits := range []uint32{0x1d00ffff, 0x207fffff, 0x1b0404cb, 0x05009234, 0x01120000} {
+		target, err := CompactToTarget(bits)
+		if err != nil {
+			t.Fatalf("CompactToTarget(%#x) error = %v", bits, err)
+		}
+		if got := TargetToCompact(target); got != bits {
+			t.Errorf("TargetToCompact(CompactToTarget(%#x)) = %#x", bits, got)
+		}
+	}
+
+	// A mantissa with the sign bit set is shifted into the next size byte
+	if got := TargetToCompact(big.NewInt(0x80)); got != 0x02008000 {
+		t.Errorf("TargetToCompact(0x80) = %#x, want %#x", got, 0x02008000)
+	}
+}
+
+func TestCompactToDifficulty(t *testing.T) {
+	if got := CompactToDifficulty(0x1d00ffff); got != 1 {
+		t.Errorf("CompactToDifficulty(0x1d00ffff) = %v, want 1", got)
+	}
+	if got := CompactToDifficulty(0x1b0404cb); got != 16307.420938523983 {
+		t.Errorf("CompactToDifficulty(0x1b0404cb) = %v, want 16307.420938523983", got)
+	}
+}
+
+func TestCompactToWork(t *testing.T) {
+	if got := CompactToWork(0x1d00ffff); got.Text(16) != "100010001" {
+		t.Errorf("CompactToWork(0x1d00ffff) = %s, want 0x100010001", got.Text(16))
+	}
+	if got := CompactToWork(0x04923456); got.Sign() != 0 {
+		t.Errorf("CompactToWork(negative) = %s, want 0", got)
+	}
+}
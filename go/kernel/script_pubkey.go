@@ -5,6 +5,7 @@ package kernel
 */
 import "C"
 import (
+	"io"
 	"unsafe"
 )
 
@@ -25,7 +26,7 @@ type ScriptPubkey struct {
 
 func newScriptPubkey(ptr *C.btck_ScriptPubkey, fromOwned bool) *ScriptPubkey {
 	h := newHandle(unsafe.Pointer(ptr), scriptPubkeyCFuncs{}, fromOwned)
-	return &ScriptPubkey{handle: h, scriptPubkeyApi: scriptPubkeyApi{(*C.btck_ScriptPubkey)(h.ptr)}}
+	return &ScriptPubkey{handle: h, scriptPubkeyApi: scriptPubkeyApi{(*C.btck_ScriptPubkey)(h.ptr), h}}
 }
 
 // NewScriptPubkey creates a new script pubkey from raw serialized script data.
@@ -48,20 +49,26 @@ type ScriptPubkeyView struct {
 	ptr *C.btck_ScriptPubkey
 }
 
-func newScriptPubkeyView(ptr *C.btck_ScriptPubkey) *ScriptPubkeyView {
+func newScriptPubkeyView(ptr *C.btck_ScriptPubkey, owner viewOwner) *ScriptPubkeyView {
 	return &ScriptPubkeyView{
-		scriptPubkeyApi: scriptPubkeyApi{ptr},
+		scriptPubkeyApi: scriptPubkeyApi{ptr, owner},
 		ptr:             ptr,
 	}
 }
 
 type scriptPubkeyApi struct {
-	ptr *C.btck_ScriptPubkey
+	ptr   *C.btck_ScriptPubkey
+	owner viewOwner
+}
+
+func (s *scriptPubkeyApi) cptr() *C.btck_ScriptPubkey {
+	checkOwner(s.owner)
+	return s.ptr
 }
 
 // Copy creates a copy of the script pubkey.
 func (s *scriptPubkeyApi) Copy() *ScriptPubkey {
-	return newScriptPubkey(s.ptr, false)
+	return newScriptPubkey(s.cptr(), false)
 }
 
 // Bytes returns the serialized representation of the script pubkey.
@@ -69,14 +76,26 @@ func (s *scriptPubkeyApi) Copy() *ScriptPubkey {
 // Returns an error if the serialization fails.
 func (s *scriptPubkeyApi) Bytes() ([]byte, error) {
 	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, user_data unsafe.Pointer) C.int {
-		return C.btck_script_pubkey_to_bytes(s.ptr, writer, user_data)
+		return C.btck_script_pubkey_to_bytes(s.cptr(), writer, user_data)
 	})
 	if !ok {
-		return nil, &SerializationError{"Failed to serialize script pubkey"}
+		return nil, &Error{Op: "serialize script pubkey", Code: ErrorCodeSerialization}
 	}
 	return bytes, nil
 }
 
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same data as Bytes.
+func (s *scriptPubkeyApi) MarshalBinary() ([]byte, error) {
+	return s.Bytes()
+}
+
+// WriteTo implements io.WriterTo, writing the script bytes to w.
+func (s *scriptPubkeyApi) WriteTo(w io.Writer) (int64, error) {
+	return writeToWriter(w, "serialize script pubkey", func(writer C.btck_WriteBytes, user_data unsafe.Pointer) C.int {
+		return C.btck_script_pubkey_to_bytes(s.cptr(), writer, user_data)
+	})
+}
+
 // Verify verifies if the input at inputIndex of txTo spends the script pubkey
 // under the constraints specified by flags. If the witness flag is set in flags,
 // the amount parameter is used. If the taproot flag is set, spentOutputs is used
@@ -116,7 +135,7 @@ func (s *scriptPubkeyApi) Verify(amount int64, txTo *Transaction, spentOutputs [
 
 	var cStatus C.btck_ScriptVerifyStatus
 	result := C.btck_script_pubkey_verify(
-		s.ptr,
+		s.cptr(),
 		C.int64_t(amount),
 		(*C.btck_Transaction)(txTo.handle.ptr),
 		cSpentOutputsPtr,
@@ -5,6 +5,8 @@ package kernel
 */
 import "C"
 import (
+	"io"
+	"iter"
 	"unsafe"
 )
 
@@ -25,7 +27,7 @@ type Transaction struct {
 
 func newTransaction(ptr *C.btck_Transaction, fromOwned bool) *Transaction {
 	h := newHandle(unsafe.Pointer(ptr), transactionCFuncs{}, fromOwned)
-	return &Transaction{handle: h, transactionApi: transactionApi{(*C.btck_Transaction)(h.ptr)}}
+	return &Transaction{handle: h, transactionApi: transactionApi{(*C.btck_Transaction)(h.ptr), h}}
 }
 
 // NewTransaction creates a new transaction from raw serialized transaction data.
@@ -35,27 +37,57 @@ func newTransaction(ptr *C.btck_Transaction, fromOwned bool) *Transaction {
 //
 // Returns an error if the transaction data is malformed or cannot be parsed.
 func NewTransaction(rawTransaction []byte) (*Transaction, error) {
+	if len(rawTransaction) == 0 {
+		return nil, &Error{Op: "create transaction", Code: ErrorCodeDeserialization}
+	}
 	ptr := C.btck_transaction_create(unsafe.Pointer(&rawTransaction[0]), C.size_t(len(rawTransaction)))
 	if ptr == nil {
-		return nil, &InternalError{"Failed to create transaction from bytes"}
+		return nil, &Error{Op: "create transaction", Code: ErrorCodeDeserialization}
 	}
 	return newTransaction(ptr, true), nil
 }
 
+// ReadTransaction reads a single transaction in consensus serialization from r. It
+// reads no further than the end of the transaction, so consecutive transactions can
+// be read from the same stream.
+//
+// Parameters:
+//   - r: Stream positioned at the start of a serialized transaction
+//
+// Returns io.EOF if r is at its end before the transaction starts, or an error with
+// code ErrorCodeDeserialization if the transaction is truncated or malformed.
+func ReadTransaction(r io.Reader) (*Transaction, error) {
+	d := newStreamDecodeReader(r)
+	d.transaction()
+	if d.err == io.EOF {
+		return nil, io.EOF
+	}
+	if d.err != nil {
+		return nil, &Error{Op: "read transaction", Code: ErrorCodeDeserialization, Err: d.err}
+	}
+	return NewTransaction(d.raw)
+}
+
 type TransactionView struct {
 	transactionApi
 	ptr *C.btck_Transaction
 }
 
-func newTransactionView(ptr *C.btck_Transaction) *TransactionView {
+func newTransactionView(ptr *C.btck_Transaction, owner viewOwner) *TransactionView {
 	return &TransactionView{
-		transactionApi: transactionApi{ptr},
+		transactionApi: transactionApi{ptr, owner},
 		ptr:            ptr,
 	}
 }
 
 type transactionApi struct {
-	ptr *C.btck_Transaction
+	ptr   *C.btck_Transaction
+	owner viewOwner
+}
+
+func (t *transactionApi) cptr() *C.btck_Transaction {
+	checkOwner(t.owner)
+	return t.ptr
 }
 
 // Copy creates a shallow copy of the transaction by incrementing its reference count.
@@ -63,17 +95,17 @@ type transactionApi struct {
 // Transactions are reference-counted internally, so this operation is efficient and does
 // not duplicate the underlying data.
 func (t *transactionApi) Copy() *Transaction {
-	return newTransaction(t.ptr, false)
+	return newTransaction(t.cptr(), false)
 }
 
 // CountInputs returns the number of inputs in the transaction.
 func (t *transactionApi) CountInputs() uint64 {
-	return uint64(C.btck_transaction_count_inputs(t.ptr))
+	return uint64(C.btck_transaction_count_inputs(t.cptr()))
 }
 
 // CountOutputs returns the number of outputs in the transaction.
 func (t *transactionApi) CountOutputs() uint64 {
-	return uint64(C.btck_transaction_count_outputs(t.ptr))
+	return uint64(C.btck_transaction_count_outputs(t.cptr()))
 }
 
 // GetOutput retrieves the output at the specified index.
@@ -83,13 +115,13 @@ func (t *transactionApi) CountOutputs() uint64 {
 // Parameters:
 //   - index: Index of the output to retrieve
 //
-// Returns an error if the index is out of bounds.
+// Returns an error with code ErrorCodeInvalidArgument if the index is out of bounds.
 func (t *transactionApi) GetOutput(index uint64) (*TransactionOutputView, error) {
 	if index >= t.CountOutputs() {
-		return nil, ErrKernelIndexOutOfBounds
+		return nil, &Error{Op: "get output", Code: ErrorCodeInvalidArgument, Err: ErrKernelIndexOutOfBounds}
 	}
-	ptr := C.btck_transaction_get_output_at(t.ptr, C.size_t(index))
-	return newTransactionOutputView(check(ptr)), nil
+	ptr := C.btck_transaction_get_output_at(t.cptr(), C.size_t(index))
+	return newTransactionOutputView(check(ptr), t.owner), nil
 }
 
 // GetInput retrieves the input at the specified index.
@@ -99,13 +131,58 @@ func (t *transactionApi) GetOutput(index uint64) (*TransactionOutputView, error)
 // Parameters:
 //   - index: Index of the input to retrieve
 //
-// Returns an error if the index is out of bounds.
+// Returns an error with code ErrorCodeInvalidArgument if the index is out of bounds.
 func (t *transactionApi) GetInput(index uint64) (*TransactionInputView, error) {
 	if index >= t.CountInputs() {
-		return nil, ErrKernelIndexOutOfBounds
+		return nil, &Error{Op: "get input", Code: ErrorCodeInvalidArgument, Err: ErrKernelIndexOutOfBounds}
+	}
+	ptr := C.btck_transaction_get_input_at(t.cptr(), C.size_t(index))
+	return newTransactionInputView(check(ptr), t.owner), nil
+}
+
+// Inputs returns an iterator over the inputs of the transaction, yielding each
+// input's index together with the input.
+//
+// The yielded inputs are non-owned views that depend on the lifetime of this transaction.
+func (t *transactionApi) Inputs() iter.Seq2[uint64, *TransactionInputView] {
+	return func(yield func(uint64, *TransactionInputView) bool) {
+		count := t.CountInputs()
+		for i := uint64(0); i < count; i++ {
+			ptr := C.btck_transaction_get_input_at(t.cptr(), C.size_t(i))
+			if !yield(i, newTransactionInputView(check(ptr), t.owner)) {
+				return
+			}
+		}
 	}
-	ptr := C.btck_transaction_get_input_at(t.ptr, C.size_t(index))
-	return newTransactionInputView(check(ptr)), nil
+}
+
+// Outputs returns an iterator over the outputs of the transaction, yielding each
+// output's index together with the output.
+//
+// The yielded outputs are non-owned views that depend on the lifetime of this transaction.
+func (t *transactionApi) Outputs() iter.Seq2[uint64, *TransactionOutputView] {
+	return func(yield func(uint64, *TransactionOutputView) bool) {
+		count := t.CountOutputs()
+		for i := uint64(0); i < count; i++ {
+			ptr := C.btck_transaction_get_output_at(t.cptr(), C.size_t(i))
+			if !yield(i, newTransactionOutputView(check(ptr), t.owner)) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same data as Bytes.
+func (t *transactionApi) MarshalBinary() ([]byte, error) {
+	return t.Bytes()
+}
+
+// WriteTo implements io.WriterTo, streaming the consensus serialization of the
+// transaction to w without buffering it.
+func (t *transactionApi) WriteTo(w io.Writer) (int64, error) {
+	return writeToWriter(w, "serialize transaction", func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_transaction_to_bytes(t.cptr(), writer, userData)
+	})
 }
 
 // Bytes returns the consensus serialized representation of the transaction.
@@ -113,16 +190,144 @@ func (t *transactionApi) GetInput(index uint64) (*TransactionInputView, error) {
 // Returns an error if the serialization fails.
 func (t *transactionApi) Bytes() ([]byte, error) {
 	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
-		return C.btck_transaction_to_bytes(t.ptr, writer, userData)
+		return C.btck_transaction_to_bytes(t.cptr(), writer, userData)
 	})
 	if !ok {
-		return nil, &SerializationError{"Failed to serialize transaction"}
+		return nil, &Error{Op: "serialize transaction", Code: ErrorCodeSerialization}
 	}
 	return bytes, nil
 }
 
 // GetTxid returns the txid for this transaction.
 func (t *transactionApi) GetTxid() *TxidView {
-	ptr := C.btck_transaction_get_txid(t.ptr)
-	return newTxidView(check(ptr))
+	ptr := C.btck_transaction_get_txid(t.cptr())
+	return newTxidView(check(ptr), t.owner)
+}
+
+// SignatureHash computes the signature hash of the input at inputIndex as it is
+// committed to by the consensus signature checks.
+//
+// The hashing scheme is selected by sigVersion. For SigVersionBase and
+// SigVersionWitnessV0 the scriptCode is the executed script (e.g. the redeem or
+// witness script). For SigVersionTapscript it is the executed tapleaf script,
+// while for SigVersionTaproot (key path) it is ignored and may be nil. If the input's
+// witness carries an annex, it is committed to as well.
+//
+// SigVersionTapscript hashes assume that no OP_CODESEPARATOR was executed before the
+// signature check, use SignatureHashTapscript to commit to its position.
+//
+// Parameters:
+//   - inputIndex: Index of the input for which the signature hash is computed
+//   - scriptCode: Script code committed to by the signature hash. May be nil for SigVersionTaproot.
+//   - amount: Amount of the output spent by the input. Only committed to by the segwit schemes.
+//   - hashType: Sighash type, e.g. SigHashAll
+//   - sigVersion: Signature hashing scheme (legacy, BIP143 or BIP341)
+//   - spentOutputs: Outputs spent by the transaction. May be nil unless a taproot scheme is selected.
+//
+// Returns an error if the signature hash cannot be computed with the given arguments.
+func (t *transactionApi) SignatureHash(inputIndex uint, scriptCode *ScriptPubkey, amount int64, hashType SigHashType,
+	sigVersion SigVersion, spentOutputs []*TransactionOutput) ([32]byte, error) {
+	return t.signatureHash(inputIndex, scriptCode, amount, hashType, sigVersion, spentOutputs, NoCodeSeparator)
+}
+
+// NoCodeSeparator is the code separator position committed to by tapscript signature
+// hashes if no OP_CODESEPARATOR was executed.
+const NoCodeSeparator uint32 = 0xFFFFFFFF
+
+// SignatureHashTapscript computes the BIP342 signature hash of a script path spend of
+// the input at inputIndex, like SignatureHash with SigVersionTapscript, but commits to
+// the position of the last OP_CODESEPARATOR executed before the signature check.
+//
+// Parameters:
+//   - inputIndex: Index of the input for which the signature hash is computed
+//   - leafScript: Executed tapleaf script (leaf version 0xc0)
+//   - codeSeparatorPos: Opcode position of the last executed OP_CODESEPARATOR in leafScript, or NoCodeSeparator
+//   - hashType: Sighash type, e.g. SigHashDefault
+//   - spentOutputs: Outputs spent by the transaction
+//
+// Returns an error if the signature hash cannot be computed with the given arguments.
+func (t *transactionApi) SignatureHashTapscript(inputIndex uint, leafScript *ScriptPubkey, codeSeparatorPos uint32,
+	hashType SigHashType, spentOutputs []*TransactionOutput) ([32]byte, error) {
+	return t.signatureHash(inputIndex, leafScript, 0, hashType, SigVersionTapscript, spentOutputs, codeSeparatorPos)
+}
+
+func (t *transactionApi) signatureHash(inputIndex uint, scriptCode *ScriptPubkey, amount int64, hashType SigHashType,
+	sigVersion SigVersion, spentOutputs []*TransactionOutput, codeSeparatorPos uint32) ([32]byte, error) {
+	if uint64(inputIndex) >= t.CountInputs() {
+		return [32]byte{}, ErrKernelIndexOutOfBounds
+	}
+
+	var cScriptCode *C.btck_ScriptPubkey
+	if scriptCode != nil {
+		cScriptCode = (*C.btck_ScriptPubkey)(scriptCode.handle.ptr)
+	} else if sigVersion != SigVersionTaproot {
+		return [32]byte{}, ErrSignatureHashScriptCodeRequired
+	}
+
+	if len(spentOutputs) > 0 && uint64(len(spentOutputs)) != t.CountInputs() {
+		return [32]byte{}, ErrSignatureHashSpentOutputsMismatch
+	}
+	isTaproot := sigVersion == SigVersionTaproot || sigVersion == SigVersionTapscript
+	if isTaproot && len(spentOutputs) == 0 {
+		return [32]byte{}, ErrSignatureHashSpentOutputsRequired
+	}
+
+	var cSpentOutputsPtr **C.btck_TransactionOutput
+	if len(spentOutputs) > 0 {
+		cSpentOutputs := make([]*C.btck_TransactionOutput, len(spentOutputs))
+		for i, output := range spentOutputs {
+			cSpentOutputs[i] = (*C.btck_TransactionOutput)(output.handle.ptr)
+		}
+		cSpentOutputsPtr = (**C.btck_TransactionOutput)(unsafe.Pointer(&cSpentOutputs[0]))
+	}
+
+	var output [32]C.uchar
+	result := C.btck_transaction_signature_hash(
+		t.cptr(),
+		C.uint(inputIndex),
+		cScriptCode,
+		C.int64_t(amount),
+		C.uint32_t(hashType),
+		sigVersion.c(),
+		cSpentOutputsPtr,
+		C.size_t(len(spentOutputs)),
+		C.uint32_t(codeSeparatorPos),
+		&output[0],
+	)
+	if result != 1 {
+		return [32]byte{}, ErrSignatureHashFailed
+	}
+	return *(*[32]byte)(unsafe.Pointer(&output[0])), nil
+}
+
+// SigHashType selects which parts of a transaction are committed to by a signature hash.
+//
+// The base types may be combined with SigHashAnyoneCanPay.
+type SigHashType uint32
+
+const (
+	SigHashDefault      SigHashType = 0x00 // Taproot only, equivalent to SigHashAll
+	SigHashAll          SigHashType = 0x01 // Commit to all inputs and outputs
+	SigHashNone         SigHashType = 0x02 // Commit to all inputs but none of the outputs
+	SigHashSingle       SigHashType = 0x03 // Commit to all inputs and the output with the same index
+	SigHashAnyoneCanPay SigHashType = 0x80 // Commit only to the input being signed
+)
+
+// SigVersion selects the signature hashing scheme.
+type SigVersion C.btck_SignatureVersion
+
+const (
+	SigVersionBase      SigVersion = C.btck_SignatureVersion_BASE       // Bare scripts and BIP16 P2SH-wrapped redeem scripts
+	SigVersionWitnessV0 SigVersion = C.btck_SignatureVersion_WITNESS_V0 // Witness v0 (P2WPKH and P2WSH), BIP143
+	SigVersionTaproot   SigVersion = C.btck_SignatureVersion_TAPROOT    // Witness v1 key path spending, BIP341
+	SigVersionTapscript SigVersion = C.btck_SignatureVersion_TAPSCRIPT  // Witness v1 script path spending with leaf version 0xc0, BIP342
+)
+
+func (v SigVersion) c() C.btck_SignatureVersion {
+	switch v {
+	case SigVersionBase, SigVersionWitnessV0, SigVersionTaproot, SigVersionTapscript:
+		return C.btck_SignatureVersion(v)
+	default:
+		panic("Invalid signature version")
+	}
 }
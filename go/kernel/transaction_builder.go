@@ -0,0 +1,130 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// SequenceFinal is the sequence number of an input that opts out of relative lock
+// times (BIP68) and replace-by-fee signalling.
+const SequenceFinal uint32 = 0xffffffff
+
+// TransactionBuilder assembles a transaction from its version, inputs, outputs and
+// lock time.
+//
+// The builder methods return the builder so calls can be chained. Errors, such as
+// an out of range input index, are recorded and returned by Build. The builder holds
+// copies of all data passed to it, so scripts and outputs may be destroyed once added.
+type TransactionBuilder struct {
+	tx  DecodedTransaction
+	err error
+}
+
+// NewTransactionBuilder creates a builder for a version 2 transaction without inputs
+// and outputs and a lock time of 0.
+func NewTransactionBuilder() *TransactionBuilder {
+	return &TransactionBuilder{tx: DecodedTransaction{Version: 2}}
+}
+
+// SetVersion sets the transaction version.
+func (b *TransactionBuilder) SetVersion(version uint32) *TransactionBuilder {
+	b.tx.Version = version
+	return b
+}
+
+// SetLockTime sets the transaction lock time, a block height if below 500000000 and
+// a unix timestamp otherwise.
+func (b *TransactionBuilder) SetLockTime(lockTime uint32) *TransactionBuilder {
+	b.tx.LockTime = lockTime
+	return b
+}
+
+// AddInput adds an input spending the output at prevIndex of the transaction with
+// txid prevTxid. Its script sig and witness are empty until set with SetScriptSig and
+// SetWitness.
+//
+// Parameters:
+//   - prevTxid: Txid of the transaction whose output is spent, e.g. from TxidView.Key
+//   - prevIndex: Index of the spent output within that transaction
+//   - sequence: Sequence number of the input, SequenceFinal if unused
+func (b *TransactionBuilder) AddInput(prevTxid TxidKey, prevIndex uint32, sequence uint32) *TransactionBuilder {
+	b.tx.Inputs = append(b.tx.Inputs, DecodedInput{
+		PrevOut:  DecodedOutPoint{Txid: prevTxid, Index: prevIndex},
+		Sequence: sequence,
+	})
+	return b
+}
+
+// SetScriptSig sets the script sig of the input at inputIndex.
+func (b *TransactionBuilder) SetScriptSig(inputIndex int, scriptSig []byte) *TransactionBuilder {
+	if input := b.input(inputIndex); input != nil {
+		input.ScriptSig = slices.Clone(scriptSig)
+	}
+	return b
+}
+
+// SetWitness sets the witness stack of the input at inputIndex. A transaction with at
+// least one non-empty witness is serialized in the extended format of BIP144.
+func (b *TransactionBuilder) SetWitness(inputIndex int, witness [][]byte) *TransactionBuilder {
+	if input := b.input(inputIndex); input != nil {
+		input.Witness = make([][]byte, len(witness))
+		for i, item := range witness {
+			input.Witness[i] = slices.Clone(item)
+		}
+	}
+	return b
+}
+
+// AddOutput adds an output paying amount to scriptPubkey.
+//
+// Parameters:
+//   - scriptPubkey: Conditions to spend the output
+//   - amount: Amount of the output in satoshis
+func (b *TransactionBuilder) AddOutput(scriptPubkey *ScriptPubkey, amount int64) *TransactionBuilder {
+	output := NewTransactionOutput(scriptPubkey, amount)
+	defer output.Destroy()
+	return b.AddTransactionOutput(output)
+}
+
+// AddTransactionOutput adds a copy of an existing output, e.g. one spent by another
+// transaction.
+func (b *TransactionBuilder) AddTransactionOutput(output *TransactionOutput) *TransactionBuilder {
+	script, err := output.ScriptPubkey().Bytes()
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	b.tx.Outputs = append(b.tx.Outputs, DecodedOutput{Amount: output.Amount(), ScriptPubkey: script})
+	return b
+}
+
+// Build serializes the assembled transaction and creates it with NewTransaction. The
+// builder can be modified and built again afterwards.
+//
+// Returns an error with code ErrorCodeInvalidArgument if a builder method failed or
+// the transaction has no inputs, which cannot be serialized unambiguously.
+func (b *TransactionBuilder) Build() (*Transaction, error) {
+	if b.err != nil {
+		return nil, &Error{Op: "build transaction", Code: ErrorCodeInvalidArgument, Err: b.err}
+	}
+	if len(b.tx.Inputs) == 0 {
+		return nil, &Error{Op: "build transaction", Code: ErrorCodeInvalidArgument, Err: errors.New("transaction has no inputs")}
+	}
+	return NewTransaction(b.tx.Bytes())
+}
+
+func (b *TransactionBuilder) input(index int) *DecodedInput {
+	if index < 0 || index >= len(b.tx.Inputs) {
+		b.setErr(fmt.Errorf("input %d of %d: %w", index, len(b.tx.Inputs), ErrKernelIndexOutOfBounds))
+		return nil
+	}
+	return &b.tx.Inputs[index]
+}
+
+// setErr records the first error encountered by a builder method.
+func (b *TransactionBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
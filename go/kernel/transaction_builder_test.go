@@ -0,0 +1,114 @@
+package kernel
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestTransactionBuilder(t *testing.T) {
+	// Rebuild the coinbase transaction of coinbaseTxHex from its fields
+	script := NewScriptPubkey(mustDecodeHex(t, "41041b0e8c2567c12536aa13357b79a073dc4444acb83c4ec7a0e2f99dd7457516c5817242da796924ca4e99947d087fedf9ce467cb9f7c6287078f801df276fdf84ac"))
+	defer script.Destroy()
+
+	tx, err := NewTransactionBuilder().
+		SetVersion(1).
+		AddInput(TxidKey{}, 0xffffffff, SequenceFinal).
+		SetScriptSig(0, mustDecodeHex(t, "044c86041b020602")).
+		AddOutput(script, 5_000_000_000).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	raw, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if hex.EncodeToString(raw) != coinbaseTxHex {
+		t.Errorf("Build() = %x, want %s", raw, coinbaseTxHex)
+	}
+}
+
+func TestTransactionBuilderSpend(t *testing.T) {
+	prevTx, err := NewTransaction(mustDecodeHex(t, coinbaseTxHex))
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer prevTx.Destroy()
+	prevOutput, err := prevTx.GetOutput(0)
+	if err != nil {
+		t.Fatalf("GetOutput() error = %v", err)
+	}
+	prevOutputCopy := prevOutput.Copy()
+	defer prevOutputCopy.Destroy()
+
+	p2wpkh := NewScriptPubkey(mustDecodeHex(t, "00141409745405c4e8310a875bcd602db6b9b3dc0cf9"))
+	defer p2wpkh.Destroy()
+
+	builder := NewTransactionBuilder().
+		AddInput(prevTx.GetTxid().Key(), 0, SequenceFinal-2).
+		AddInput(prevTx.GetTxid().Key(), 1, SequenceFinal).
+		SetWitness(1, [][]byte{{0x01, 0x02}, {0x03}}).
+		AddOutput(p2wpkh, 4_999_990_000).
+		AddTransactionOutput(prevOutputCopy).
+		SetLockTime(100)
+	tx, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	if tx.CountInputs() != 2 || tx.CountOutputs() != 2 {
+		t.Fatalf("Build() has %d inputs and %d outputs, want 2 and 2", tx.CountInputs(), tx.CountOutputs())
+	}
+	decoded, err := tx.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Version != 2 || decoded.LockTime != 100 {
+		t.Errorf("Decode() version %d, lock time %d, want 2 and 100", decoded.Version, decoded.LockTime)
+	}
+	if decoded.Inputs[0].PrevOut.Txid != prevTx.GetTxid().Bytes() || decoded.Inputs[0].Sequence != SequenceFinal-2 {
+		t.Errorf("Unexpected first input %+v", decoded.Inputs[0])
+	}
+	if !decoded.HasWitness() || len(decoded.Inputs[1].Witness) != 2 {
+		t.Errorf("Expected the witness of the second input, got %v", decoded.Inputs[1].Witness)
+	}
+	if decoded.Txid == decoded.Wtxid {
+		t.Error("Expected txid and wtxid of a witness transaction to differ")
+	}
+	if decoded.Outputs[0].Amount != 4_999_990_000 || decoded.Outputs[1].Amount != prevOutputCopy.Amount() {
+		t.Errorf("Unexpected output amounts %+v", decoded.Outputs)
+	}
+	prevScript, _ := prevOutputCopy.ScriptPubkey().Bytes()
+	if !bytes.Equal(decoded.Outputs[1].ScriptPubkey, prevScript) {
+		t.Errorf("Copied output script = %x, want %x", decoded.Outputs[1].ScriptPubkey, prevScript)
+	}
+
+	// The builder can be extended and built again
+	second, err := builder.AddOutput(p2wpkh, 1).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	defer second.Destroy()
+	if second.CountOutputs() != 3 {
+		t.Errorf("Rebuilt transaction has %d outputs, want 3", second.CountOutputs())
+	}
+}
+
+func TestTransactionBuilderErrors(t *testing.T) {
+	if _, err := NewTransactionBuilder().Build(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Build() without inputs error = %v, want %v", err, ErrInvalidArgument)
+	}
+
+	_, err := NewTransactionBuilder().
+		AddInput(TxidKey{}, 0, SequenceFinal).
+		SetScriptSig(1, []byte{0x51}).
+		Build()
+	if !errors.Is(err, ErrKernelIndexOutOfBounds) || !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Build() with invalid input index error = %v, want %v", err, ErrKernelIndexOutOfBounds)
+	}
+}
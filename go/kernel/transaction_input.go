@@ -26,7 +26,7 @@ type TransactionInput struct {
 
 func newTransactionInput(ptr *C.btck_TransactionInput, fromOwned bool) *TransactionInput {
 	h := newHandle(unsafe.Pointer(ptr), transactionInputCFuncs{}, fromOwned)
-	return &TransactionInput{handle: h, transactionInputApi: transactionInputApi{(*C.btck_TransactionInput)(h.ptr)}}
+	return &TransactionInput{handle: h, transactionInputApi: transactionInputApi{(*C.btck_TransactionInput)(h.ptr), h}}
 }
 
 // TransactionInputView holds information on the TransactionOutPoint held within.
@@ -35,24 +35,30 @@ type TransactionInputView struct {
 	ptr *C.btck_TransactionInput
 }
 
-func newTransactionInputView(ptr *C.btck_TransactionInput) *TransactionInputView {
+func newTransactionInputView(ptr *C.btck_TransactionInput, owner viewOwner) *TransactionInputView {
 	return &TransactionInputView{
-		transactionInputApi: transactionInputApi{ptr},
+		transactionInputApi: transactionInputApi{ptr, owner},
 		ptr:                 ptr,
 	}
 }
 
 type transactionInputApi struct {
-	ptr *C.btck_TransactionInput
+	ptr   *C.btck_TransactionInput
+	owner viewOwner
+}
+
+func (t *transactionInputApi) cptr() *C.btck_TransactionInput {
+	checkOwner(t.owner)
+	return t.ptr
 }
 
 // Copy creates a copy of the transaction input.
 func (t *transactionInputApi) Copy() *TransactionInput {
-	return newTransactionInput(t.ptr, false)
+	return newTransactionInput(t.cptr(), false)
 }
 
 // GetOutPoint returns the transaction out point.
 func (t *transactionInputApi) GetOutPoint() *TransactionOutPointView {
-	ptr := C.btck_transaction_input_get_out_point(t.ptr)
-	return newTransactionOutPointView(check(ptr))
+	ptr := C.btck_transaction_input_get_out_point(t.cptr())
+	return newTransactionOutPointView(check(ptr), t.owner)
 }
@@ -26,7 +26,7 @@ type TransactionOutPoint struct {
 
 func newTransactionOutPoint(ptr *C.btck_TransactionOutPoint, fromOwned bool) *TransactionOutPoint {
 	h := newHandle(unsafe.Pointer(ptr), transactionOutPointCFuncs{}, fromOwned)
-	return &TransactionOutPoint{handle: h, transactionOutPointApi: transactionOutPointApi{(*C.btck_TransactionOutPoint)(h.ptr)}}
+	return &TransactionOutPoint{handle: h, transactionOutPointApi: transactionOutPointApi{(*C.btck_TransactionOutPoint)(h.ptr), h}}
 }
 
 // TransactionOutPointView holds the txid and output index it is pointing to.
@@ -35,29 +35,35 @@ type TransactionOutPointView struct {
 	ptr *C.btck_TransactionOutPoint
 }
 
-func newTransactionOutPointView(ptr *C.btck_TransactionOutPoint) *TransactionOutPointView {
+func newTransactionOutPointView(ptr *C.btck_TransactionOutPoint, owner viewOwner) *TransactionOutPointView {
 	return &TransactionOutPointView{
-		transactionOutPointApi: transactionOutPointApi{ptr},
+		transactionOutPointApi: transactionOutPointApi{ptr, owner},
 		ptr:                    ptr,
 	}
 }
 
 type transactionOutPointApi struct {
-	ptr *C.btck_TransactionOutPoint
+	ptr   *C.btck_TransactionOutPoint
+	owner viewOwner
+}
+
+func (t *transactionOutPointApi) cptr() *C.btck_TransactionOutPoint {
+	checkOwner(t.owner)
+	return t.ptr
 }
 
 // Copy creates a copy of the transaction out point.
 func (t *transactionOutPointApi) Copy() *TransactionOutPoint {
-	return newTransactionOutPoint(t.ptr, false)
+	return newTransactionOutPoint(t.cptr(), false)
 }
 
 // GetIndex returns the output position from the transaction out point.
 func (t *transactionOutPointApi) GetIndex() uint32 {
-	return uint32(C.btck_transaction_out_point_get_index(t.ptr))
+	return uint32(C.btck_transaction_out_point_get_index(t.cptr()))
 }
 
 // GetTxid returns the txid from the out point.
 func (t *transactionOutPointApi) GetTxid() *TxidView {
-	ptr := C.btck_transaction_out_point_get_txid(t.ptr)
-	return newTxidView(check(ptr))
+	ptr := C.btck_transaction_out_point_get_txid(t.cptr())
+	return newTxidView(check(ptr), t.owner)
 }
@@ -25,7 +25,7 @@ type TransactionOutput struct {
 
 func newTransactionOutput(ptr *C.btck_TransactionOutput, fromOwned bool) *TransactionOutput {
 	h := newHandle(unsafe.Pointer(ptr), transactionOutputCFuncs{}, fromOwned)
-	return &TransactionOutput{handle: h, transactionOutputApi: transactionOutputApi{(*C.btck_TransactionOutput)(h.ptr)}}
+	return &TransactionOutput{handle: h, transactionOutputApi: transactionOutputApi{(*C.btck_TransactionOutput)(h.ptr), h}}
 }
 
 // NewTransactionOutput creates a transaction output from a script pubkey and an amount.
@@ -43,20 +43,26 @@ type TransactionOutputView struct {
 	ptr *C.btck_TransactionOutput
 }
 
-func newTransactionOutputView(ptr *C.btck_TransactionOutput) *TransactionOutputView {
+func newTransactionOutputView(ptr *C.btck_TransactionOutput, owner viewOwner) *TransactionOutputView {
 	return &TransactionOutputView{
-		transactionOutputApi: transactionOutputApi{ptr},
+		transactionOutputApi: transactionOutputApi{ptr, owner},
 		ptr:                  ptr,
 	}
 }
 
 type transactionOutputApi struct {
-	ptr *C.btck_TransactionOutput
+	ptr   *C.btck_TransactionOutput
+	owner viewOwner
+}
+
+func (t *transactionOutputApi) cptr() *C.btck_TransactionOutput {
+	checkOwner(t.owner)
+	return t.ptr
 }
 
 // Copy creates a copy of the transaction output.
 func (t *transactionOutputApi) Copy() *TransactionOutput {
-	return newTransactionOutput(t.ptr, false)
+	return newTransactionOutput(t.cptr(), false)
 }
 
 // ScriptPubkey returns the script pubkey of this output.
@@ -64,11 +70,11 @@ func (t *transactionOutputApi) Copy() *TransactionOutput {
 // The returned ScriptPubkeyView is a non-owned pointer valid for the lifetime of
 // this transaction output.
 func (t *transactionOutputApi) ScriptPubkey() *ScriptPubkeyView {
-	ptr := C.btck_transaction_output_get_script_pubkey(t.ptr)
-	return newScriptPubkeyView(check(ptr))
+	ptr := C.btck_transaction_output_get_script_pubkey(t.cptr())
+	return newScriptPubkeyView(check(ptr), t.owner)
 }
 
 // Amount returns the amount in the output
 func (t *transactionOutputApi) Amount() int64 {
-	return int64(C.btck_transaction_output_get_amount(t.ptr))
+	return int64(C.btck_transaction_output_get_amount(t.cptr()))
 }
@@ -5,6 +5,7 @@ package kernel
 */
 import "C"
 import (
+	"iter"
 	"unsafe"
 )
 
@@ -29,7 +30,7 @@ type TransactionSpentOutputs struct {
 
 func newTransactionSpentOutputs(ptr *C.btck_TransactionSpentOutputs, fromOwned bool) *TransactionSpentOutputs {
 	h := newHandle(unsafe.Pointer(ptr), transactionSpentOutputsCFuncs{}, fromOwned)
-	return &TransactionSpentOutputs{handle: h, transactionSpentOutputsApi: transactionSpentOutputsApi{(*C.btck_TransactionSpentOutputs)(h.ptr)}}
+	return &TransactionSpentOutputs{handle: h, transactionSpentOutputsApi: transactionSpentOutputsApi{(*C.btck_TransactionSpentOutputs)(h.ptr), h}}
 }
 
 type TransactionSpentOutputsView struct {
@@ -37,25 +38,31 @@ type TransactionSpentOutputsView struct {
 	ptr *C.btck_TransactionSpentOutputs
 }
 
-func newTransactionSpentOutputsView(ptr *C.btck_TransactionSpentOutputs) *TransactionSpentOutputsView {
+func newTransactionSpentOutputsView(ptr *C.btck_TransactionSpentOutputs, owner viewOwner) *TransactionSpentOutputsView {
 	return &TransactionSpentOutputsView{
-		transactionSpentOutputsApi: transactionSpentOutputsApi{ptr},
+		transactionSpentOutputsApi: transactionSpentOutputsApi{ptr, owner},
 		ptr:                        ptr,
 	}
 }
 
 type transactionSpentOutputsApi struct {
-	ptr *C.btck_TransactionSpentOutputs
+	ptr   *C.btck_TransactionSpentOutputs
+	owner viewOwner
+}
+
+func (t *transactionSpentOutputsApi) cptr() *C.btck_TransactionSpentOutputs {
+	checkOwner(t.owner)
+	return t.ptr
 }
 
 // Copy creates a copy of the transaction spent outputs.
 func (t *transactionSpentOutputsApi) Copy() *TransactionSpentOutputs {
-	return newTransactionSpentOutputs(t.ptr, false)
+	return newTransactionSpentOutputs(t.cptr(), false)
 }
 
 // Count returns the number of previous transaction outputs contained in the transaction spent outputs data.
 func (t *transactionSpentOutputsApi) Count() uint64 {
-	return uint64(C.btck_transaction_spent_outputs_count(t.ptr))
+	return uint64(C.btck_transaction_spent_outputs_count(t.cptr()))
 }
 
 // GetCoinAt returns a coin contained in the transaction spent outputs at a
@@ -65,11 +72,28 @@ func (t *transactionSpentOutputsApi) Count() uint64 {
 // Parameters:
 //   - index: The index of the to be retrieved coin within the transaction spent outputs
 //
-// Returns an error if the index is out of bounds.
+// Returns an error with code ErrorCodeInvalidArgument if the index is out of bounds.
 func (t *transactionSpentOutputsApi) GetCoinAt(index uint64) (*CoinView, error) {
 	if index >= t.Count() {
-		return nil, ErrKernelIndexOutOfBounds
+		return nil, &Error{Op: "get coin", Code: ErrorCodeInvalidArgument, Err: ErrKernelIndexOutOfBounds}
+	}
+	ptr := C.btck_transaction_spent_outputs_get_coin_at(t.cptr(), C.size_t(index))
+	return newCoinView(check(ptr), t.owner), nil
+}
+
+// Coins returns an iterator over the coins spent by the transaction, yielding the index
+// of each coin (matching the index of the input spending it) together with the coin.
+//
+// The yielded CoinViews are unowned and only valid for the lifetime of the transaction
+// spent outputs.
+func (t *transactionSpentOutputsApi) Coins() iter.Seq2[uint64, *CoinView] {
+	return func(yield func(uint64, *CoinView) bool) {
+		count := t.Count()
+		for i := uint64(0); i < count; i++ {
+			ptr := C.btck_transaction_spent_outputs_get_coin_at(t.cptr(), C.size_t(i))
+			if !yield(i, newCoinView(check(ptr), t.owner)) {
+				return
+			}
+		}
 	}
-	ptr := C.btck_transaction_spent_outputs_get_coin_at(t.ptr, C.size_t(index))
-	return newCoinView(check(ptr)), nil
 }
@@ -1,8 +1,10 @@
 package kernel
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
+	"io"
 	"testing"
 )
 
@@ -12,9 +14,13 @@ const coinbaseTxHex = "010000000100000000000000000000000000000000000000000000000
 func TestInvalidTransactionData(t *testing.T) {
 	// Test with invalid data
 	_, err := NewTransaction([]byte{0x00, 0x01, 0x02})
-	var internalErr *InternalError
-	if !errors.As(err, &internalErr) {
-		t.Errorf("Expected InternalError, got %v", err)
+	var kernelErr *Error
+	if !errors.As(err, &kernelErr) || kernelErr.Code != ErrorCodeDeserialization {
+		t.Errorf("Expected Error with code %v, got %v", ErrorCodeDeserialization, err)
+	}
+
+	if _, err := NewTransaction(nil); !errors.Is(err, ErrDeserialization) {
+		t.Errorf("NewTransaction(nil) error = %v, want %v", err, ErrDeserialization)
 	}
 }
 
@@ -69,7 +75,8 @@ func TestTransaction(t *testing.T) {
 		t.Error("Input is nil")
 	}
 	_, err = tx.GetInput(inputCount)
-	if !errors.Is(err, ErrKernelIndexOutOfBounds) {
+	var kernelErr *Error
+	if !errors.Is(err, ErrKernelIndexOutOfBounds) || !errors.As(err, &kernelErr) || kernelErr.Code != ErrorCodeInvalidArgument {
 		t.Errorf("Expected ErrKernelIndexOutOfBounds for out of bounds input, got %v", err)
 	}
 
@@ -104,3 +111,301 @@ func TestTransaction(t *testing.T) {
 		t.Errorf("Serialized transaction doesn't match original.\nExpected: %s\nGot: %s", coinbaseTxHex, hex.EncodeToString(serialized))
 	}
 }
+
+func TestTransactionUseAfterDestroy(t *testing.T) {
+	tx, err := NewTransaction(mustDecodeHex(t, coinbaseTxHex))
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	input, err := tx.GetInput(0)
+	if err != nil {
+		t.Fatalf("GetInput(0) error = %v", err)
+	}
+	txCopy := tx.Copy()
+	defer txCopy.Destroy()
+
+	tx.Destroy()
+
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { tx.CountInputs() })
+	assertPanicsWith(t, ErrKernelUseAfterDestroy, func() { input.GetOutPoint() })
+
+	// Copies own their data and are unaffected
+	if txCopy.CountInputs() != 1 {
+		t.Errorf("Expected 1 input in copy, got %d", txCopy.CountInputs())
+	}
+}
+
+func TestTransactionIterators(t *testing.T) {
+	// BIP143 native P2WPKH example, spending two outputs and creating two outputs
+	txHex := "0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000"
+	tx, err := NewTransaction(mustDecodeHex(t, txHex))
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	wantOutPointIndexes := []uint32{0, 1}
+	var inputCount int
+	for i, input := range tx.Inputs() {
+		if i != uint64(inputCount) {
+			t.Errorf("Inputs() yielded index %d, want %d", i, inputCount)
+		}
+		if got := input.GetOutPoint().GetIndex(); got != wantOutPointIndexes[i] {
+			t.Errorf("Input %d out point index = %d, want %d", i, got, wantOutPointIndexes[i])
+		}
+		inputCount++
+	}
+	if inputCount != 2 {
+		t.Errorf("Inputs() yielded %d inputs, want 2", inputCount)
+	}
+
+	wantAmounts := []int64{112340000, 223450000}
+	var outputCount int
+	for i, output := range tx.Outputs() {
+		if i != uint64(outputCount) {
+			t.Errorf("Outputs() yielded index %d, want %d", i, outputCount)
+		}
+		if got := output.Amount(); got != wantAmounts[i] {
+			t.Errorf("Output %d amount = %d, want %d", i, got, wantAmounts[i])
+		}
+		outputCount++
+	}
+	if outputCount != 2 {
+		t.Errorf("Outputs() yielded %d outputs, want 2", outputCount)
+	}
+
+	// Breaking out of the loop stops the iteration
+	outputCount = 0
+	for range tx.Outputs() {
+		outputCount++
+		break
+	}
+	if outputCount != 1 {
+		t.Errorf("Outputs() yielded %d outputs after break, want 1", outputCount)
+	}
+}
+
+func TestTransactionSignatureHash(t *testing.T) {
+	// BIP341 key path spending test vector (bip341_wallet_vectors.json)
+	taprootTxHex := "02000000097de20cbff686da83a54981d2b9bab3586f4ca7e48f57f5b55963115f3b334e9c010000000000000000d7b7cab57b1393ace2d064f4d4a2cb8af6def61273e127517d44759b6dafdd990000000000fffffffff8e1f583384333689228c5d28eac13366be082dc57441760d957275419a418420000000000fffffffff0689180aa63b30cb162a73c6d2a38b7eeda2a83ece74310fda0843ad604853b0100000000feffffffaa5202bdf6d8ccd2ee0f0202afbbb7461d9264a25e5bfd3c5a52ee1239e0ba6c0000000000feffffff956149bdc66faa968eb2be2d2faa29718acbfe3941215893a2a3446d32acd050000000000000000000e664b9773b88c09c32cb70a2a3e4da0ced63b7ba3b22f848531bbb1d5d5f4c94010000000000000000e9aa6b8e6c9de67619e6a3924ae25696bb7b694bb677a632a74ef7eadfd4eabf0000000000ffffffffa778eb6a263dc090464cd125c466b5a99667720b1c110468831d058aa1b82af10100000000ffffffff0200ca9a3b000000001976a91406afd46bcdfd22ef94ac122aa11f241244a37ecc88ac807840cb0000000020ac9a87f5594be208f8532db38cff670c450ed2fea8fcdefcc9a663f78bab962b0065cd1d"
+	taprootSpent := []struct {
+		scriptPubkeyHex string
+		amount          int64
+	}{
+		{"512053a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343", 420000000},
+		{"5120147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3", 462000000},
+		{"76a914751e76e8199196d454941c45d1b3a323f1433bd688ac", 294000000},
+		{"5120e4d810fd50586274face62b8a807eb9719cef49c04177cc6b76a9a4251d5450e", 504000000},
+		{"512091b64d5324723a985170e4dc5a0f84c041804f2cd12660fa5dec09fc21783605", 630000000},
+		{"00147dd65592d0ab2fe0d0257d571abf032cd9db93dc", 378000000},
+		{"512075169f4001aa68f15bbed28b218df1d0a62cbbcf1188c6665110c293c907b831", 672000000},
+		{"5120712447206d7a5238acc7ff53fbe94a3b64539ad291c7cdbc490b7577e4b17df5", 546000000},
+		{"512077e30a5522dd9f894c3f8b8bd4c4b2cf82ca7da8a3ea6a239655c39c050ab220", 588000000},
+	}
+	var spentOutputs []*TransactionOutput
+	for _, utxo := range taprootSpent {
+		scriptBytes, err := hex.DecodeString(utxo.scriptPubkeyHex)
+		if err != nil {
+			t.Fatalf("Failed to decode script pubkey hex: %v", err)
+		}
+		scriptPubkey := NewScriptPubkey(scriptBytes)
+		defer scriptPubkey.Destroy()
+		output := NewTransactionOutput(scriptPubkey, utxo.amount)
+		defer output.Destroy()
+		spentOutputs = append(spentOutputs, output)
+	}
+
+	tests := []struct {
+		name          string
+		txHex         string
+		inputIndex    uint
+		scriptCodeHex string
+		amount        int64
+		hashType      SigHashType
+		sigVersion    SigVersion
+		spentOutputs  []*TransactionOutput
+		wantHashHex   string
+	}{
+		{
+			// sighash.json, the expected hash is given in reversed (display) byte order
+			name:          "legacy",
+			txHex:         "907c2bc503ade11cc3b04eb2918b6f547b0630ab569273824748c87ea14b0696526c66ba740200000004ab65ababfd1f9bdd4ef073c7afc4ae00da8a66f429c917a0081ad1e1dabce28d373eab81d8628de802000000096aab5253ab52000052ad042b5f25efb33beec9f3364e8a9139e8439d9d7e26529c3c30b6c3fd89f8684cfd68ea0200000009ab53526500636a52ab599ac2fe02a526ed040000000008535300516352515164370e010000000003006300ab2ec229",
+			inputIndex:    2,
+			scriptCodeHex: "",
+			hashType:      SigHashType(1864164639),
+			sigVersion:    SigVersionBase,
+			wantHashHex:   hex.EncodeToString(reverseBytes(mustDecodeHex(t, "31af167a6cf3f9d5f6875caa4d31704ceb0eba078d132b78dab52c3b8997317e"))),
+		},
+		{
+			// BIP143 native P2WPKH example
+			name:          "segwit_v0",
+			txHex:         "0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000",
+			inputIndex:    1,
+			scriptCodeHex: "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac",
+			amount:        600000000,
+			hashType:      SigHashAll,
+			sigVersion:    SigVersionWitnessV0,
+			wantHashHex:   "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670",
+		},
+		{
+			name:         "taproot_key_path_single",
+			txHex:        taprootTxHex,
+			inputIndex:   0,
+			hashType:     SigHashSingle,
+			sigVersion:   SigVersionTaproot,
+			spentOutputs: spentOutputs,
+			wantHashHex:  "2514a6272f85cfa0f45eb907fcb0d121b808ed37c6ea160a5a9046ed5526d555",
+		},
+		{
+			name:         "taproot_key_path_single_anyonecanpay",
+			txHex:        taprootTxHex,
+			inputIndex:   1,
+			hashType:     SigHashSingle | SigHashAnyoneCanPay,
+			sigVersion:   SigVersionTaproot,
+			spentOutputs: spentOutputs,
+			wantHashHex:  "325a644af47e8a5a2591cda0ab0723978537318f10e6a63d4eed783b96a71a4d",
+		},
+		{
+			name:         "taproot_key_path_default",
+			txHex:        taprootTxHex,
+			inputIndex:   4,
+			hashType:     SigHashDefault,
+			sigVersion:   SigVersionTaproot,
+			spentOutputs: spentOutputs,
+			wantHashHex:  "4f900a0bae3f1446fd48490c2958b5a023228f01661cda3496a11da502a7f7ef",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewTransaction(mustDecodeHex(t, tt.txHex))
+			if err != nil {
+				t.Fatalf("NewTransaction() error = %v", err)
+			}
+			defer tx.Destroy()
+
+			var scriptCode *ScriptPubkey
+			if tt.sigVersion != SigVersionTaproot {
+				scriptCode = NewScriptPubkey(mustDecodeHex(t, tt.scriptCodeHex))
+				defer scriptCode.Destroy()
+			}
+
+			hash, err := tx.SignatureHash(tt.inputIndex, scriptCode, tt.amount, tt.hashType, tt.sigVersion, tt.spentOutputs)
+			if err != nil {
+				t.Fatalf("SignatureHash() error = %v", err)
+			}
+			if hex.EncodeToString(hash[:]) != tt.wantHashHex {
+				t.Errorf("SignatureHash() = %x, want %s", hash, tt.wantHashHex)
+			}
+		})
+	}
+
+	// BIP342 script path spends of the BIP341 vector transaction with the leaf script
+	// OP_CODESEPARATOR <32-byte key> OP_CHECKSIG. The expected hashes follow the BIP341
+	// signature message with ext_flag 1 and the tapleaf extension.
+	t.Run("tapscript", func(t *testing.T) {
+		tx, err := NewTransaction(mustDecodeHex(t, taprootTxHex))
+		if err != nil {
+			t.Fatalf("NewTransaction() error = %v", err)
+		}
+		defer tx.Destroy()
+
+		leafScript := NewScriptPubkey(mustDecodeHex(t, "ab20d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961dac"))
+		defer leafScript.Destroy()
+
+		tapscriptTests := []struct {
+			name             string
+			inputIndex       uint
+			hashType         SigHashType
+			codeSeparatorPos uint32
+			wantHashHex      string
+		}{
+			{"no_codeseparator", 3, SigHashDefault, NoCodeSeparator, "1f8e2f64e89ddea2411f1f1477215ad3a5f6722cf96e21e45edc89f441d1ecb4"},
+			{"codeseparator", 3, SigHashDefault, 0, "cf20d3f85b6068575c0f2a27f9ae63edf36890b4eb6da68833b5529391d0d30e"},
+			{"codeseparator_all_anyonecanpay", 6, SigHashAll | SigHashAnyoneCanPay, 0, "68f8cff843a3cce1b41220c1271f8543729671c29f2c879f6fd759a4cdec1d73"},
+		}
+		for _, tt := range tapscriptTests {
+			hash, err := tx.SignatureHashTapscript(tt.inputIndex, leafScript, tt.codeSeparatorPos, tt.hashType, spentOutputs)
+			if err != nil {
+				t.Fatalf("%s: SignatureHashTapscript() error = %v", tt.name, err)
+			}
+			if hex.EncodeToString(hash[:]) != tt.wantHashHex {
+				t.Errorf("%s: SignatureHashTapscript() = %x, want %s", tt.name, hash, tt.wantHashHex)
+			}
+		}
+
+		// SignatureHash assumes that no code separator was executed
+		hash, err := tx.SignatureHash(3, leafScript, 0, SigHashDefault, SigVersionTapscript, spentOutputs)
+		if err != nil {
+			t.Fatalf("SignatureHash() error = %v", err)
+		}
+		if hex.EncodeToString(hash[:]) != tapscriptTests[0].wantHashHex {
+			t.Errorf("SignatureHash() = %x, want %s", hash, tapscriptTests[0].wantHashHex)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tx, err := NewTransaction(mustDecodeHex(t, taprootTxHex))
+		if err != nil {
+			t.Fatalf("NewTransaction() error = %v", err)
+		}
+		defer tx.Destroy()
+
+		_, err = tx.SignatureHash(uint(tx.CountInputs()), nil, 0, SigHashDefault, SigVersionTaproot, spentOutputs)
+		if !errors.Is(err, ErrKernelIndexOutOfBounds) {
+			t.Errorf("Expected ErrKernelIndexOutOfBounds, got %v", err)
+		}
+		_, err = tx.SignatureHash(0, nil, 0, SigHashDefault, SigVersionTaproot, nil)
+		if !errors.Is(err, ErrSignatureHashSpentOutputsRequired) {
+			t.Errorf("Expected ErrSignatureHashSpentOutputsRequired, got %v", err)
+		}
+		_, err = tx.SignatureHash(0, nil, 0, SigHashDefault, SigVersionTaproot, spentOutputs[:1])
+		if !errors.Is(err, ErrSignatureHashSpentOutputsMismatch) {
+			t.Errorf("Expected ErrSignatureHashSpentOutputsMismatch, got %v", err)
+		}
+		_, err = tx.SignatureHash(0, nil, 0, SigHashDefault, SigVersionTapscript, spentOutputs)
+		if !errors.Is(err, ErrSignatureHashScriptCodeRequired) {
+			t.Errorf("Expected ErrSignatureHashScriptCodeRequired, got %v", err)
+		}
+		_, err = tx.SignatureHash(0, nil, 0, SigHashType(0x04), SigVersionTaproot, spentOutputs)
+		if !errors.Is(err, ErrSignatureHashFailed) {
+			t.Errorf("Expected ErrSignatureHashFailed for invalid taproot hash type, got %v", err)
+		}
+	})
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Failed to decode hex %q: %v", s, err)
+	}
+	return b
+}
+
+func TestReadTransactionFromStream(t *testing.T) {
+	txBytes := mustDecodeHex(t, coinbaseTxHex)
+	stream := io.MultiReader(bytes.NewReader(txBytes), bytes.NewReader(txBytes))
+
+	for i := 0; i < 2; i++ {
+		tx, err := ReadTransaction(stream)
+		if err != nil {
+			t.Fatalf("ReadTransaction() #%d error = %v", i, err)
+		}
+		var buf bytes.Buffer
+		if _, err := tx.WriteTo(&buf); err != nil {
+			t.Fatalf("WriteTo() error = %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), txBytes) {
+			t.Errorf("WriteTo() = %x, want %x", buf.Bytes(), txBytes)
+		}
+		tx.Destroy()
+	}
+	if _, err := ReadTransaction(stream); err != io.EOF {
+		t.Errorf("ReadTransaction() at end of stream error = %v, want %v", err, io.EOF)
+	}
+
+	if _, err := ReadTransaction(bytes.NewReader(txBytes[:20])); !errors.Is(err, ErrDeserialization) {
+		t.Errorf("ReadTransaction() of truncated transaction error = %v, want %v", err, ErrDeserialization)
+	}
+}
@@ -5,6 +5,7 @@ package kernel
 */
 import "C"
 import (
+	"encoding/json"
 	"unsafe"
 )
 
@@ -18,6 +19,7 @@ func (txidCFuncs) copy(ptr unsafe.Pointer) unsafe.Pointer {
 	return unsafe.Pointer(C.btck_txid_copy((*C.btck_Txid)(ptr)))
 }
 
+// Txid is a type-safe identifier for a transaction.
 type Txid struct {
 	*handle
 	txidApi
@@ -25,38 +27,174 @@ type Txid struct {
 
 func newTxid(ptr *C.btck_Txid, fromOwned bool) *Txid {
 	h := newHandle(unsafe.Pointer(ptr), txidCFuncs{}, fromOwned)
-	return &Txid{handle: h, txidApi: txidApi{(*C.btck_Txid)(h.ptr)}}
+	return &Txid{handle: h, txidApi: txidApi{(*C.btck_Txid)(h.ptr), h}}
 }
 
+// TxidView is a type-safe identifier for a transaction.
 type TxidView struct {
 	txidApi
 	ptr *C.btck_Txid
 }
 
-func newTxidView(ptr *C.btck_Txid) *TxidView {
+func newTxidView(ptr *C.btck_Txid, owner viewOwner) *TxidView {
 	return &TxidView{
-		txidApi: txidApi{ptr},
+		txidApi: txidApi{ptr, owner},
 		ptr:     ptr,
 	}
 }
 
 type txidApi struct {
-	ptr *C.btck_Txid
+	ptr   *C.btck_Txid
+	owner viewOwner
+}
+
+func (t *txidApi) cptr() *C.btck_Txid {
+	checkOwner(t.owner)
+	return t.ptr
+}
+
+func (t *txidApi) txidPtr() *C.btck_Txid {
+	return t.cptr()
+}
+
+// TxidLike is an interface for types that can provide a txid pointer.
+type TxidLike interface {
+	txidPtr() *C.btck_Txid
+}
+
+// NewTxid creates a new Txid from a 32-byte hash value.
+//
+// Parameters:
+//   - txidBytes: 32-byte array containing the txid in internal byte order
+func NewTxid(txidBytes [32]byte) *Txid {
+	ptr := C.btck_txid_create((*C.uchar)(unsafe.Pointer(&txidBytes[0])))
+	return newTxid(check(ptr), true)
+}
+
+// ParseTxid creates a new Txid from its hex display form, as shown by bitcoind and
+// block explorers.
+//
+// Parameters:
+//   - s: 64 hex characters in display byte order
+//
+// Returns an error with code ErrorCodeDeserialization if s is not a valid txid.
+func ParseTxid(s string) (*Txid, error) {
+	txid, err := parseHash("parse txid", s)
+	if err != nil {
+		return nil, err
+	}
+	return NewTxid(txid), nil
 }
 
 // Copy creates a copy of the txid.
 func (t *txidApi) Copy() *Txid {
-	return newTxid(t.ptr, false)
+	return newTxid(t.cptr(), false)
 }
 
 // Equals checks if two txids are equal.
-func (t *txidApi) Equals(other *Txid) bool {
-	return C.btck_txid_equals(t.ptr, other.txidApi.ptr) != 0
+//
+// Parameters:
+//   - other: Txid to compare against (can be *Txid or *TxidView)
+//
+// Returns true if the txids are equal.
+func (t *txidApi) Equals(other TxidLike) bool {
+	return C.btck_txid_equals(t.cptr(), other.txidPtr()) != 0
 }
 
 // Bytes returns the 32-byte representation of the txid.
 func (t *txidApi) Bytes() [32]byte {
 	var output [32]C.uchar
-	C.btck_txid_to_bytes(t.ptr, &output[0])
+	C.btck_txid_to_bytes(t.cptr(), &output[0])
 	return *(*[32]byte)(unsafe.Pointer(&output[0]))
 }
+
+// Key returns the txid as a comparable value, e.g. for use as a map key.
+func (t *txidApi) Key() TxidKey {
+	return t.Bytes()
+}
+
+// String returns the hex encoding of the txid in display byte order, as shown by
+// bitcoind.
+func (t *txidApi) String() string {
+	return t.Key().String()
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (t *txidApi) MarshalText() ([]byte, error) {
+	return t.Key().MarshalText()
+}
+
+// MarshalJSON encodes the txid as a JSON string in its String form.
+func (t *txidApi) MarshalJSON() ([]byte, error) {
+	return t.Key().MarshalJSON()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the txid with the one
+// parsed from its String form.
+func (t *Txid) UnmarshalText(text []byte) error {
+	txid, err := parseHash("parse txid", string(text))
+	if err != nil {
+		return err
+	}
+	t.replace(txid)
+	return nil
+}
+
+// UnmarshalJSON replaces the txid with the one parsed from a JSON string in its
+// String form.
+func (t *Txid) UnmarshalJSON(data []byte) error {
+	txid, err := unmarshalHashJSON("parse txid", data)
+	if err != nil {
+		return err
+	}
+	t.replace(txid)
+	return nil
+}
+
+// replace destroys the current txid, if any, and points t to a new one.
+func (t *Txid) replace(txid [32]byte) {
+	if t.handle != nil {
+		t.Destroy()
+	}
+	*t = *NewTxid(txid)
+}
+
+// TxidKey is a txid as a plain value in the internal byte order used by Bytes.
+// Unlike Txid it does not reference kernel memory, so it is comparable, can be used
+// as a map key and needs no Destroy.
+type TxidKey [32]byte
+
+// String returns the hex encoding of the txid in display byte order.
+func (k TxidKey) String() string {
+	return hashString(k)
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (k TxidKey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the String form.
+func (k *TxidKey) UnmarshalText(text []byte) error {
+	txid, err := parseHash("parse txid", string(text))
+	if err != nil {
+		return err
+	}
+	*k = txid
+	return nil
+}
+
+// MarshalJSON encodes the txid as a JSON string in its String form.
+func (k TxidKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// UnmarshalJSON parses a JSON string in the String form.
+func (k *TxidKey) UnmarshalJSON(data []byte) error {
+	txid, err := unmarshalHashJSON("parse txid", data)
+	if err != nil {
+		return err
+	}
+	*k = txid
+	return nil
+}
@@ -2,6 +2,8 @@ package kernel
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -40,3 +42,60 @@ func TestTxid(t *testing.T) {
 		t.Error("txid.Equals(copiedTxid) = false, want true")
 	}
 }
+
+func TestTxidString(t *testing.T) {
+	tx, err := NewTransaction(mustDecodeHex(t, coinbaseTxHex))
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer tx.Destroy()
+	view := tx.GetTxid()
+
+	parsed, err := ParseTxid(view.String())
+	if err != nil {
+		t.Fatalf("ParseTxid() error = %v", err)
+	}
+	defer parsed.Destroy()
+
+	// Equals accepts both owned txids and views
+	if !parsed.Equals(view) || !view.Equals(parsed) {
+		t.Errorf("ParseTxid(%s) does not equal the transaction's txid", view)
+	}
+	if parsed.Key() != view.Key() {
+		t.Errorf("Key() = %s, want %s", parsed.Key(), view.Key())
+	}
+
+	fromBytes := NewTxid(view.Bytes())
+	defer fromBytes.Destroy()
+	if !fromBytes.Equals(view) {
+		t.Errorf("NewTxid(%x) does not equal the transaction's txid", view.Bytes())
+	}
+
+	if _, err := ParseTxid("1234"); !errors.Is(err, ErrDeserialization) {
+		t.Errorf("ParseTxid() error = %v, want %v", err, ErrDeserialization)
+	}
+}
+
+func TestTxidJSON(t *testing.T) {
+	key := TxidKey{0xaa, 0xbb}
+	const want = `"000000000000000000000000000000000000000000000000000000000000bbaa"`
+
+	data, err := json.Marshal(key)
+	if err != nil || string(data) != want {
+		t.Errorf("json.Marshal() = %s, %v, want %s", data, err, want)
+	}
+
+	var decoded TxidKey
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != key {
+		t.Errorf("json.Unmarshal() = %s, %v, want %s", decoded, err, key)
+	}
+
+	var txid Txid
+	if err := json.Unmarshal(data, &txid); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	defer txid.Destroy()
+	if txid.Key() != key {
+		t.Errorf("json.Unmarshal() = %s, want %s", txid.Key(), key)
+	}
+}
@@ -19,38 +19,68 @@ type ValidationInterfaceCallbacks struct {
 	OnBlockDisconnected func(block *Block, entry *BlockTreeEntry)       // Called during a re-org when a block has been removed from the best chain.
 }
 
+// validationTarget is the user data of the validation interface bridges.
+type validationTarget struct {
+	callbacks *ValidationInterfaceCallbacks
+	queue     *callbackQueue
+}
+
 //export go_validation_interface_block_checked_bridge
 func go_validation_interface_block_checked_bridge(user_data unsafe.Pointer, block *C.btck_Block, state *C.btck_BlockValidationState) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	defer recoverCallbackPanic("OnBlockChecked")
+
+	target := cgo.Handle(user_data).Value().(*validationTarget)
+	callbacks := target.callbacks
 	if callbacks.OnBlockChecked != nil {
-		callbacks.OnBlockChecked(newBlock(block, true), &BlockValidationState{ptr: state})
+		goBlock, goState := newBlock(block, true), &BlockValidationState{ptr: state}
+		if target.queue.async {
+			// The state is only valid during the callback, keep a copy of its results
+			goState = goState.capture()
+		}
+		if !target.queue.dispatch("OnBlockChecked", func() { callbacks.OnBlockChecked(goBlock, goState) }) {
+			goBlock.Destroy()
+		}
 	}
 }
 
 //export go_validation_interface_pow_valid_block_bridge
 func go_validation_interface_pow_valid_block_bridge(user_data unsafe.Pointer, block *C.btck_Block, entry *C.btck_BlockTreeEntry) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	defer recoverCallbackPanic("OnPoWValidBlock")
+
+	target := cgo.Handle(user_data).Value().(*validationTarget)
+	callbacks := target.callbacks
 	if callbacks.OnPoWValidBlock != nil {
-		callbacks.OnPoWValidBlock(newBlock(block, true), &BlockTreeEntry{ptr: entry})
+		goBlock, goEntry := newBlock(block, true), &BlockTreeEntry{ptr: entry}
+		if !target.queue.dispatch("OnPoWValidBlock", func() { callbacks.OnPoWValidBlock(goBlock, goEntry) }) {
+			goBlock.Destroy()
+		}
 	}
 }
 
 //export go_validation_interface_block_connected_bridge
 func go_validation_interface_block_connected_bridge(user_data unsafe.Pointer, block *C.btck_Block, entry *C.btck_BlockTreeEntry) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	defer recoverCallbackPanic("OnBlockConnected")
+
+	target := cgo.Handle(user_data).Value().(*validationTarget)
+	callbacks := target.callbacks
 	if callbacks.OnBlockConnected != nil {
-		callbacks.OnBlockConnected(newBlock(block, true), &BlockTreeEntry{ptr: entry})
+		goBlock, goEntry := newBlock(block, true), &BlockTreeEntry{ptr: entry}
+		if !target.queue.dispatch("OnBlockConnected", func() { callbacks.OnBlockConnected(goBlock, goEntry) }) {
+			goBlock.Destroy()
+		}
 	}
 }
 
 //export go_validation_interface_block_disconnected_bridge
 func go_validation_interface_block_disconnected_bridge(user_data unsafe.Pointer, block *C.btck_Block, entry *C.btck_BlockTreeEntry) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	defer recoverCallbackPanic("OnBlockDisconnected")
+
+	target := cgo.Handle(user_data).Value().(*validationTarget)
+	callbacks := target.callbacks
 	if callbacks.OnBlockDisconnected != nil {
-		callbacks.OnBlockDisconnected(newBlock(block, true), &BlockTreeEntry{ptr: entry})
+		goBlock, goEntry := newBlock(block, true), &BlockTreeEntry{ptr: entry}
+		if !target.queue.dispatch("OnBlockDisconnected", func() { callbacks.OnBlockDisconnected(goBlock, goEntry) }) {
+			goBlock.Destroy()
+		}
 	}
 }
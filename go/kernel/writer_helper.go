@@ -9,22 +9,45 @@ extern int go_writer_callback_bridge(void* bytes, size_t size, void* userdata);
 */
 import "C"
 import (
+	"io"
 	"runtime/cgo"
 	"unsafe"
 )
 
-// writerCallbackData holds the growing buffer that collects written bytes
+// writerCallbackData holds the growing buffer that collects written bytes, or the
+// writer they are forwarded to
 type writerCallbackData struct {
 	buffer []byte
+
+	w     io.Writer
+	n     int64
+	err   error
+	panic any // value of a panic raised by w, re-raised once the kernel returned
 }
 
 //export go_writer_callback_bridge
-func go_writer_callback_bridge(bytes unsafe.Pointer, size C.size_t, userdata unsafe.Pointer) C.int {
+func go_writer_callback_bridge(bytes unsafe.Pointer, size C.size_t, userdata unsafe.Pointer) (result C.int) {
 	if size > 0 {
 		data := cgo.Handle(userdata).Value().(*writerCallbackData)
+		defer func() {
+			if r := recover(); r != nil {
+				data.panic = r
+				result = -1
+			}
+		}()
 		// Create a Go slice view of the C memory
 		cBytes := unsafe.Slice((*byte)(bytes), int(size))
-		data.buffer = append(data.buffer, cBytes...)
+		if data.w == nil {
+			data.buffer = append(data.buffer, cBytes...)
+			return 0
+		}
+		n, err := data.w.Write(cBytes)
+		data.n += int64(n)
+		if err != nil {
+			// Abort the serialization, the kernel does not call the writer again
+			data.err = err
+			return -1
+		}
 	}
 	return 0
 }
@@ -42,3 +65,26 @@ func writeToBytes(writerFunc func(C.btck_WriteBytes, unsafe.Pointer) C.int) (byt
 	}
 	return callbackData.buffer, true
 }
+
+// writeToWriter is like writeToBytes, but streams the bytes to w as the kernel
+// produces them, without buffering the whole serialization. It implements the
+// io.WriterTo contract: it returns the number of bytes written and the first error
+// encountered, wrapped in an Error for op. A panic raised by w is recovered before it
+// reaches the kernel and raised again once the kernel has returned.
+func writeToWriter(w io.Writer, op string, writerFunc func(C.btck_WriteBytes, unsafe.Pointer) C.int) (int64, error) {
+	callbackData := &writerCallbackData{w: w}
+	handle := cgo.NewHandle(callbackData)
+	defer handle.Delete()
+
+	result := writerFunc((C.btck_WriteBytes)(C.go_writer_callback_bridge), unsafe.Pointer(handle))
+	if callbackData.panic != nil {
+		panic(callbackData.panic)
+	}
+	if callbackData.err != nil {
+		return callbackData.n, &Error{Op: op, Code: ErrorCodeSerialization, Err: callbackData.err}
+	}
+	if result != 0 {
+		return callbackData.n, &Error{Op: op, Code: ErrorCodeSerialization}
+	}
+	return callbackData.n, nil
+}
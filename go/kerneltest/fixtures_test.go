@@ -0,0 +1,26 @@
+package kerneltest
+
+import (
+	"testing"
+)
+
+func TestRegtestBlocks(t *testing.T) {
+	blocks := RegtestBlocks(t)
+	if got := RegtestHeight(t); got != int32(len(blocks)) || got == 0 {
+		t.Fatalf("RegtestHeight() = %d, want %d", got, len(blocks))
+	}
+
+	decoded, err := RegtestBlock(t, 2).Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	first := RegtestBlock(t, 1).Hash()
+	defer first.Destroy()
+	if decoded.Header.PrevBlock != first.Bytes() {
+		t.Errorf("Block 2 does not build on block 1")
+	}
+
+	if _, err := readBlocksFile(RegtestBlocksFile() + ".missing"); err == nil {
+		t.Error("Expected error reading missing fixture")
+	}
+}
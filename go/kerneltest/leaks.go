@@ -0,0 +1,34 @@
+package kerneltest
+
+import (
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+// VerifyNoLeaks fails the test if a kernel handle created after this call is not
+// destroyed by the time the test and its cleanups complete, see
+// kernel.VerifyNoHandleLeaks. Call it first in the test, so that its check runs after
+// the cleanups registered by the other helpers of this package.
+func VerifyNoLeaks(t testing.TB) {
+	t.Helper()
+	kernel.VerifyNoHandleLeaks(t)
+}
+
+// RequireHandleTracking skips the test unless handles are tracked, i.e. it is built
+// with the kerneldebug build tag.
+func RequireHandleTracking(t testing.TB) {
+	t.Helper()
+	if !kernel.HandleTrackingEnabled {
+		t.Skip("Handle tracking requires the kerneldebug build tag")
+	}
+}
+
+// AssertNoLiveHandles fails the test if any kernel handle is alive, listing where each
+// one was created. It has no effect unless built with the kerneldebug build tag.
+func AssertNoLiveHandles(t testing.TB) {
+	t.Helper()
+	for _, info := range kernel.LiveHandles() {
+		t.Errorf("kernel handle #%d (%s) is alive, created at:\n%s", info.ID, info.Type, info.Stack)
+	}
+}
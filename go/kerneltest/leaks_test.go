@@ -0,0 +1,15 @@
+package kerneltest
+
+import (
+	"testing"
+)
+
+func TestAssertNoLiveHandles(t *testing.T) {
+	RequireHandleTracking(t)
+
+	node := NewRegtestNode(t, 1)
+	if err := node.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	AssertNoLiveHandles(t)
+}
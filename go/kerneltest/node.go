@@ -0,0 +1,80 @@
+package kerneltest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+// AllBlocks syncs a chainstate to the last block of the regtest fixture when passed
+// as height to NewRegtestNode.
+const AllBlocks int32 = -1
+
+// TempDataDir creates data and blocks directories below a temporary directory that
+// is removed when the test completes.
+func TempDataDir(t testing.TB) (dataDir, blocksDir string) {
+	t.Helper()
+	root := t.TempDir()
+	return filepath.Join(root, "data"), filepath.Join(root, "blocks")
+}
+
+// NewRegtestNode opens a regtest node in a temporary data directory and processes the
+// fixture blocks up to and including height, or all of them for AllBlocks. Pass 0 for
+// a chainstate holding only the genesis block.
+//
+// The node uses in-memory databases and a single script verification thread unless
+// overridden by options, which are applied after these defaults. It is closed when the
+// test completes.
+//
+// Fails the test if the node cannot be opened or a block is rejected.
+func NewRegtestNode(t testing.TB, height int32, options ...kernel.NodeOption) *kernel.Node {
+	t.Helper()
+	dataDir, blocksDir := TempDataDir(t)
+	defaults := []kernel.NodeOption{
+		kernel.WithChain(kernel.ChainTypeRegtest),
+		kernel.WithBlocksDir(blocksDir),
+		kernel.WithInMemoryDBs(),
+		kernel.WithWorkerThreads(1),
+	}
+	node, err := kernel.OpenNode(dataDir, append(defaults, options...)...)
+	if err != nil {
+		t.Fatalf("OpenNode() error = %v", err)
+	}
+	t.Cleanup(func() { node.Close() })
+
+	if height == AllBlocks {
+		height = RegtestHeight(t)
+	}
+	ProcessRegtestBlocks(t, node.ChainstateManager(), 1, height)
+	return node
+}
+
+// ProcessRegtestBlocks processes the fixture blocks from height from up to and
+// including height to.
+//
+// Fails the test if a block cannot be loaded or is rejected.
+func ProcessRegtestBlocks(t testing.TB, manager *kernel.ChainstateManager, from, to int32) {
+	t.Helper()
+	for height := from; height <= to; height++ {
+		block, err := kernel.NewBlock(RegtestBlockBytes(t, height))
+		if err != nil {
+			t.Fatalf("NewBlock() failed for regtest block %d: %v", height, err)
+		}
+		ok, duplicate := manager.ProcessBlock(block)
+		block.Destroy()
+		if !ok || duplicate {
+			t.Fatalf("ProcessBlock() failed for regtest block %d: ok = %v, duplicate = %v", height, ok, duplicate)
+		}
+	}
+}
+
+// TipHeight returns the height of the active chain tip of manager, or -1 if the chain
+// is empty.
+func TipHeight(manager *kernel.ChainstateManager) int32 {
+	tip := manager.GetActiveChain().GetTip()
+	if tip == nil {
+		return -1
+	}
+	return tip.Height()
+}
@@ -0,0 +1,32 @@
+package kerneltest
+
+import (
+	"testing"
+)
+
+func TestNewRegtestNode(t *testing.T) {
+	VerifyNoLeaks(t)
+
+	node := NewRegtestNode(t, 10)
+	manager := node.ChainstateManager()
+	if got := TipHeight(manager); got != 10 {
+		t.Fatalf("TipHeight() = %d, want 10", got)
+	}
+
+	ProcessRegtestBlocks(t, manager, 11, 12)
+	if got := TipHeight(manager); got != 12 {
+		t.Errorf("TipHeight() = %d, want 12", got)
+	}
+
+	genesisOnly := NewRegtestNode(t, 0)
+	if got := TipHeight(genesisOnly.ChainstateManager()); got != 0 {
+		t.Errorf("TipHeight() = %d, want 0", got)
+	}
+}
+
+func TestNewRegtestNodeAllBlocks(t *testing.T) {
+	node := NewRegtestNode(t, AllBlocks)
+	if got, want := TipHeight(node.ChainstateManager()), RegtestHeight(t); got != want {
+		t.Errorf("TipHeight() = %d, want %d", got, want)
+	}
+}
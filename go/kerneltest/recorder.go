@@ -0,0 +1,84 @@
+package kerneltest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+// EventRecorder records kernel notifications and validation interface callbacks as
+// kernel.Event values, in the order they were raised, for inspection after the fact.
+//
+// Blocks of recorded validation events are owned by the recorder and destroyed by
+// Reset or when the test completes, so they must not be destroyed by the test.
+type EventRecorder struct {
+	mu     sync.Mutex
+	events []kernel.Event
+}
+
+// NewEventRecorder creates a recorder whose recorded events are released when the test
+// completes.
+func NewEventRecorder(t testing.TB) *EventRecorder {
+	r := &EventRecorder{}
+	t.Cleanup(r.Reset)
+	return r
+}
+
+// NodeOptions returns the options registering the recorder with a node, e.g. for
+// NewRegtestNode.
+func (r *EventRecorder) NodeOptions() []kernel.NodeOption {
+	return []kernel.NodeOption{
+		kernel.WithNotifications(r.NotificationCallbacks()),
+		kernel.WithValidationInterface(r.ValidationInterfaceCallbacks()),
+	}
+}
+
+// Events returns the events recorded so far.
+func (r *EventRecorder) Events() []kernel.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]kernel.Event(nil), r.events...)
+}
+
+// Reset discards the events recorded so far and destroys their blocks.
+func (r *EventRecorder) Reset() {
+	r.mu.Lock()
+	events := r.events
+	r.events = nil
+	r.mu.Unlock()
+
+	for _, event := range events {
+		kernel.ReleaseEvent(event)
+	}
+}
+
+// EventsOf returns the recorded events of type T, e.g.
+// EventsOf[kernel.BlockConnectedEvent](recorder).
+func EventsOf[T kernel.Event](r *EventRecorder) []T {
+	var events []T
+	for _, event := range r.Events() {
+		if e, ok := event.(T); ok {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// NotificationCallbacks returns notification callbacks that record to r.
+func (r *EventRecorder) NotificationCallbacks() *kernel.NotificationCallbacks {
+	notifications, _ := kernel.EventCallbacks(r.record)
+	return notifications
+}
+
+// ValidationInterfaceCallbacks returns validation interface callbacks that record to r.
+func (r *EventRecorder) ValidationInterfaceCallbacks() *kernel.ValidationInterfaceCallbacks {
+	_, validation := kernel.EventCallbacks(r.record)
+	return validation
+}
+
+func (r *EventRecorder) record(event kernel.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
@@ -0,0 +1,39 @@
+package kerneltest
+
+import (
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+func TestEventRecorder(t *testing.T) {
+	VerifyNoLeaks(t)
+
+	recorder := NewEventRecorder(t)
+	NewRegtestNode(t, 3, recorder.NodeOptions()...)
+
+	connected := EventsOf[kernel.BlockConnectedEvent](recorder)
+	if len(connected) != 3 {
+		t.Fatalf("Recorded %d connected blocks, want 3", len(connected))
+	}
+	for i, e := range connected {
+		if e.Entry.Height != int32(i+1) {
+			t.Errorf("Connected block %d at height %d", i, e.Entry.Height)
+		}
+		hash := e.Block.Hash()
+		if hash.Key() != e.Entry.Hash {
+			t.Errorf("Connected block hash %s does not match entry hash %s", hash, e.Entry.Hash)
+		}
+		hash.Destroy()
+	}
+
+	tips := EventsOf[kernel.BlockTipEvent](recorder)
+	if len(tips) == 0 || tips[len(tips)-1].Tip.Height != 3 {
+		t.Errorf("Expected last block tip at height 3, got %v", tips)
+	}
+
+	recorder.Reset()
+	if events := recorder.Events(); len(events) != 0 {
+		t.Errorf("Expected no events after Reset(), got %d", len(events))
+	}
+}